@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DementevVV/commitsum/internal/domain/entity"
+	"github.com/DementevVV/commitsum/internal/infrastructure/checksum"
+	"github.com/DementevVV/commitsum/internal/infrastructure/logger"
+	"github.com/DementevVV/commitsum/internal/usecase"
+)
+
+// Exit codes.
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitUsage       = 2
+	exitNoCommits   = 3
+	exitUnchanged   = 4
+	exitInterrupted = 130
+)
+
+// usage prints command-line help including exit codes.
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: commitsum [flags]\n\n")
+	fmt.Fprintf(out, "Without flags, commitsum starts the interactive terminal UI.\n\n")
+	fmt.Fprintf(out, "Flags:\n")
+	flag.PrintDefaults()
+	fmt.Fprintf(out, "\nExit codes (non-interactive modes):\n")
+	fmt.Fprintf(out, "  %-3d success, commits found\n", exitOK)
+	fmt.Fprintf(out, "  %-3d error\n", exitError)
+	fmt.Fprintf(out, "  %-3d invalid flags\n", exitUsage)
+	fmt.Fprintf(out, "  %-3d success, but no commits in the range\n", exitNoCommits)
+	fmt.Fprintf(out, "  %-3d report unchanged since the last run (--skip-if-unchanged), nothing printed\n", exitUnchanged)
+	fmt.Fprintf(out, "  %-3d interrupted\n", exitInterrupted)
+}
+
+// repoCount is a single entry of the --list-repos output.
+type repoCount struct {
+	Repo  string `json:"repo"`
+	Count int    `json:"count"`
+}
+
+// resolvePreset resolves a non-custom date range preset key.
+func resolvePreset(key string) (entity.DateRange, error) {
+	for _, preset := range entity.DateRangePresets {
+		if preset.Key == key && key != "custom" {
+			return entity.GetDateRange(key), nil
+		}
+	}
+	return entity.DateRange{}, fmt.Errorf("unknown range %q", key)
+}
+
+// resolveSinceUntil resolves --since/--until expressions to a date range.
+// A missing bound defaults to today.
+func resolveSinceUntil(since, until string) (entity.DateRange, error) {
+	if since == "" {
+		since = "today"
+	}
+	if until == "" {
+		until = "today"
+	}
+
+	start, err := entity.ParseNaturalDate(since, false)
+	if err != nil {
+		return entity.DateRange{}, fmt.Errorf("invalid --since: %w", err)
+	}
+	end, err := entity.ParseNaturalDate(until, true)
+	if err != nil {
+		return entity.DateRange{}, fmt.Errorf("invalid --until: %w", err)
+	}
+	if start > end {
+		return entity.DateRange{}, fmt.Errorf("--since %s is after --until %s", start, end)
+	}
+	return entity.DateRange{StartDate: start, EndDate: end, Label: "Custom"}, nil
+}
+
+// readStdinRange reads the first line of r and parses it as a range.
+func readStdinRange(r io.Reader) (entity.DateRange, error) {
+	data, err := io.ReadAll(io.LimitReader(r, 4096))
+	if err != nil {
+		return entity.DateRange{}, fmt.Errorf("failed to read range from stdin: %w", err)
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	return entity.ParseRange(line)
+}
+
+// stdinFormats are the export formats accepted by --format.
+var stdinFormats = []entity.ExportFormat{
+	entity.FormatText, entity.FormatMarkdown, entity.FormatJSON, entity.FormatHeatmap,
+	entity.FormatPRBody, entity.FormatGitHubTasks, entity.FormatDaily, entity.FormatLegacyJSON,
+	entity.FormatCompact,
+}
+
+// isStdinFormat reports whether format is accepted by --format.
+func isStdinFormat(format string) bool {
+	return slices.Contains(stdinFormats, entity.ExportFormat(format))
+}
+
+// runExport fetches a range and prints it in the given format, selecting
+// every repository. The report checksum is written to stderr only when asked
+// for (printChecksum) or needed (skipIfUnchanged), so scripts that treat any
+// stderr output as a failure keep working.
+func runExport(commitUC *usecase.CommitUseCase, exportUC *usecase.ExportUseCase, dr entity.DateRange, format entity.ExportFormat, opts entity.ExportOptions, skipIfUnchanged, printChecksum bool) int {
+	data, err := commitUC.GetCommitsForRange(dr.StartDate, dr.EndDate)
+	if err != nil {
+		logger.Error("Failed to export range", "error", err.Error())
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+
+	selected := make(map[string]bool, len(data.RepoList))
+	for _, repo := range data.RepoList {
+		selected[repo] = true
+	}
+	stats := commitUC.CalculateStatistics(data.Commits, selected)
+	dateStr := entity.FormatDateDisplay(dr.StartDate, dr.EndDate)
+
+	sum := exportUC.Checksum(data.Commits, selected, opts)
+	if skipIfUnchanged || printChecksum {
+		// The checksum goes to stderr so stdout stays the report itself.
+		fmt.Fprintf(os.Stderr, "checksum %s\n", sum)
+	}
+	var sums *checksum.FileStore
+	// The checksum only covers commits, so each format keeps its own entry.
+	sumKey := dr.StartDate + ".." + dr.EndDate + "|" + string(format)
+	if skipIfUnchanged {
+		sums, err = checksum.NewFileStore()
+		if err != nil {
+			logger.Warn("Failed to open checksum store", "error", err.Error())
+		} else if prev, ok, err := sums.Get(sumKey); err != nil {
+			logger.Warn("Failed to read previous checksum", "error", err.Error())
+		} else if ok && prev == sum {
+			logger.Info("Report unchanged, skipping", "key", sumKey)
+			return exitUnchanged
+		}
+	}
+
+	content, err := exportUC.Render(format, data.Commits, selected, dateStr, stats, func() entity.Heatmap {
+		return commitUC.BuildHeatmap(data.Commits, selected)
+	}, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+	if processed, err := exportUC.PostProcess(content); err != nil {
+		logger.Warn("Post-process command failed", "error", err.Error())
+		fmt.Fprintf(os.Stderr, "Warning: post-process command failed, using unprocessed output: %v\n", err)
+	} else {
+		content = processed
+	}
+
+	fmt.Print(content)
+	if !strings.HasSuffix(content, "\n") {
+		fmt.Println()
+	}
+	if sums != nil {
+		if err := sums.Set(sumKey, sum); err != nil {
+			logger.Warn("Failed to store checksum", "error", err.Error())
+		}
+	}
+	if len(data.RepoList) == 0 {
+		return exitNoCommits
+	}
+	return exitOK
+}
+
+// parseWeekdays converts configured weekday names; config validation has
+// already dropped unknown names.
+func parseWeekdays(names []string) []time.Weekday {
+	var days []time.Weekday
+	for _, name := range names {
+		if day, ok := entity.ParseWeekday(name); ok {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// runListRepos prints the repositories with commits in a range as JSON.
+func runListRepos(commitUC *usecase.CommitUseCase, dr entity.DateRange) int {
+	data, err := commitUC.GetCommitsForRange(dr.StartDate, dr.EndDate)
+	if err != nil {
+		logger.Error("Failed to list repositories", "error", err.Error())
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+
+	selected := make(map[string]bool, len(data.RepoList))
+	for _, repo := range data.RepoList {
+		selected[repo] = true
+	}
+	stats := commitUC.CalculateStatistics(data.Commits, selected)
+
+	repos := make([]repoCount, 0, len(data.RepoList))
+	for _, repo := range data.RepoList {
+		repos = append(repos, repoCount{Repo: repo, Count: stats.CommitsPerRepo[repo]})
+	}
+
+	out, err := json.MarshalIndent(repos, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+
+	fmt.Println(string(out))
+	if len(repos) == 0 {
+		return exitNoCommits
+	}
+	return exitOK
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/DementevVV/commitsum/internal/domain/entity"
+	"github.com/DementevVV/commitsum/internal/testutil"
+	"github.com/DementevVV/commitsum/internal/usecase"
+)
+
+// captureStdout runs fn and returns what it wrote to stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	return capture(t, &os.Stdout, fn)
+}
+
+// captureStderr runs fn and returns what it wrote to stderr.
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	return capture(t, &os.Stderr, fn)
+}
+
+// capture runs fn with *f redirected to a pipe and returns what was written.
+func capture(t *testing.T, f **os.File, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := *f
+	*f = w
+	defer func() { *f = orig }()
+
+	out := make(chan []byte)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- b
+	}()
+	fn()
+	w.Close()
+	return string(<-out)
+}
+
+// sampleCommits is a small fixture across two repositories.
+func sampleCommits() map[string][]entity.Commit {
+	return map[string][]entity.Commit{
+		"acme/api": {
+			{Repository: "acme/api", Message: "Add login", SHA: "a1"},
+			{Repository: "acme/api", Message: "Fix logout", SHA: "a2"},
+		},
+		"acme/web": {
+			{Repository: "acme/web", Message: "Style header", SHA: "w1"},
+		},
+	}
+}
+
+func TestRunListRepos(t *testing.T) {
+	tests := []struct {
+		name     string
+		commits  map[string][]entity.Commit
+		err      error
+		want     []repoCount
+		wantCode int
+	}{
+		{
+			name:     "repos with counts",
+			commits:  sampleCommits(),
+			want:     []repoCount{{Repo: "acme/api", Count: 2}, {Repo: "acme/web", Count: 1}},
+			wantCode: exitOK,
+		},
+		{
+			name:     "no commits",
+			commits:  nil,
+			want:     []repoCount{},
+			wantCode: exitNoCommits,
+		},
+		{
+			name:     "fetch error",
+			err:      io.ErrUnexpectedEOF,
+			wantCode: exitError,
+		},
+	}
+
+	dr := entity.DateRange{StartDate: "2024-03-01", EndDate: "2024-03-07"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gh := testutil.NewFakeGitHub(tt.commits)
+			gh.Err = tt.err
+			commitUC := usecase.NewCommitUseCase(gh, testutil.NewFakeCache())
+
+			var code int
+			out := captureStdout(t, func() { code = runListRepos(commitUC, dr) })
+			if code != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d", code, tt.wantCode)
+			}
+			if tt.err != nil {
+				if out != "" {
+					t.Errorf("stdout = %q on error, want empty", out)
+				}
+				return
+			}
+
+			var got []repoCount
+			if err := json.Unmarshal([]byte(out), &got); err != nil {
+				t.Fatalf("output is not JSON: %v\n%s", err, out)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("entry %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRunExportExitCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		commits  map[string][]entity.Commit
+		err      error
+		wantCode int
+	}{
+		{name: "commits found", commits: sampleCommits(), wantCode: exitOK},
+		{name: "no commits", commits: nil, wantCode: exitNoCommits},
+		{name: "fetch error", err: io.ErrUnexpectedEOF, wantCode: exitError},
+	}
+
+	dr := entity.DateRange{StartDate: "2024-03-01", EndDate: "2024-03-07"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gh := testutil.NewFakeGitHub(tt.commits)
+			gh.Err = tt.err
+			commitUC := usecase.NewCommitUseCase(gh, testutil.NewFakeCache())
+
+			var code int
+			captureStdout(t, func() {
+				code = runExport(commitUC, usecase.NewExportUseCase(), dr, entity.FormatText, entity.ExportOptions{}, false, false)
+			})
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestRunExportChecksum(t *testing.T) {
+	tests := []struct {
+		name            string
+		skipIfUnchanged bool
+		printChecksum   bool
+		wantChecksum    bool
+	}{
+		{name: "plain export", wantChecksum: false},
+		{name: "print checksum", printChecksum: true, wantChecksum: true},
+		{name: "skip if unchanged", skipIfUnchanged: true, wantChecksum: true},
+	}
+
+	dr := entity.DateRange{StartDate: "2024-03-01", EndDate: "2024-03-07"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			commitUC := usecase.NewCommitUseCase(testutil.NewFakeGitHub(sampleCommits()), testutil.NewFakeCache())
+
+			stderr := captureStderr(t, func() {
+				captureStdout(t, func() {
+					runExport(commitUC, usecase.NewExportUseCase(), dr, entity.FormatText, entity.ExportOptions{}, tt.skipIfUnchanged, tt.printChecksum)
+				})
+			})
+			if got := strings.HasPrefix(stderr, "checksum "); got != tt.wantChecksum {
+				t.Errorf("stderr = %q, want checksum printed %v", stderr, tt.wantChecksum)
+			}
+		})
+	}
+}
+
+func TestRunExportSkipIfUnchangedPerFormat(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	commitUC := usecase.NewCommitUseCase(testutil.NewFakeGitHub(sampleCommits()), testutil.NewFakeCache())
+	dr := entity.DateRange{StartDate: "2024-03-01", EndDate: "2024-03-07"}
+
+	steps := []struct {
+		format   entity.ExportFormat
+		wantCode int
+	}{
+		{format: entity.FormatMarkdown, wantCode: exitOK},
+		{format: entity.FormatJSON, wantCode: exitOK},
+		{format: entity.FormatJSON, wantCode: exitUnchanged},
+		{format: entity.FormatMarkdown, wantCode: exitUnchanged},
+	}
+	for i, step := range steps {
+		var code int
+		captureStderr(t, func() {
+			out := captureStdout(t, func() {
+				code = runExport(commitUC, usecase.NewExportUseCase(), dr, step.format, entity.ExportOptions{}, true, false)
+			})
+			if (code == exitOK) != (out != "") {
+				t.Errorf("step %d (%s): exit %d with stdout %q", i, step.format, code, out)
+			}
+		})
+		if code != step.wantCode {
+			t.Errorf("step %d (%s): exit code = %d, want %d", i, step.format, code, step.wantCode)
+		}
+	}
+}
+
+func TestUsageDocumentsExitCodes(t *testing.T) {
+	var buf strings.Builder
+	flag.CommandLine.SetOutput(&buf)
+	t.Cleanup(func() { flag.CommandLine.SetOutput(nil) })
+
+	usage()
+	for _, code := range []int{exitOK, exitError, exitUsage, exitNoCommits, exitUnchanged, exitInterrupted} {
+		if !strings.Contains(buf.String(), fmt.Sprintf("  %-3d ", code)) {
+			t.Errorf("usage does not document exit code %d", code)
+		}
+	}
+}
+
+func TestResolveSinceUntil(t *testing.T) {
+	today := entity.Today()
+
+	tests := []struct {
+		name    string
+		since   string
+		until   string
+		want    entity.DateRange
+		wantErr string
+	}{
+		{
+			name:  "ISO dates",
+			since: "2024-03-01", until: "2024-03-07",
+			want: entity.DateRange{StartDate: "2024-03-01", EndDate: "2024-03-07", Label: "Custom"},
+		},
+		{
+			name:  "until defaults to today",
+			since: "2024-03-01",
+			want:  entity.DateRange{StartDate: "2024-03-01", EndDate: today, Label: "Custom"},
+		},
+		{
+			name: "both default to today",
+			want: entity.DateRange{StartDate: today, EndDate: today, Label: "Custom"},
+		},
+		{name: "bad since", since: "someday", wantErr: "invalid --since"},
+		{name: "bad until", since: "2024-03-01", until: "someday", wantErr: "invalid --until"},
+		{name: "reversed", since: "2024-03-07", until: "2024-03-01", wantErr: "is after --until"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveSinceUntil(tt.since, tt.until)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveSinceUntil() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// failingReader returns err on every read.
+type failingReader struct{ err error }
+
+func (r failingReader) Read([]byte) (int, error) { return 0, r.err }
+
+func TestReadStdinRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   io.Reader
+		want    entity.DateRange
+		wantErr string
+	}{
+		{name: "range", input: strings.NewReader("2024-03-01..2024-03-07\n"), want: entity.DateRange{StartDate: "2024-03-01", EndDate: "2024-03-07", Label: "Custom"}},
+		{name: "first line only", input: strings.NewReader("2024-03-01\n2024-04-01..2024-04-02\n"), want: entity.DateRange{StartDate: "2024-03-01", EndDate: "2024-03-01", Label: "Custom"}},
+		{name: "no newline", input: strings.NewReader("2024-03-01..2024-03-07"), want: entity.DateRange{StartDate: "2024-03-01", EndDate: "2024-03-07", Label: "Custom"}},
+		{name: "empty", input: strings.NewReader(""), wantErr: "empty range"},
+		{name: "malformed", input: strings.NewReader("march\n"), wantErr: "invalid range start"},
+		{name: "read error", input: failingReader{err: io.ErrUnexpectedEOF}, wantErr: "failed to read range from stdin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readStdinRange(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readStdinRange() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readStdinRange(): %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("readStdinRange() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
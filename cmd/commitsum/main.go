@@ -5,16 +5,24 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/DementevVV/commitsum/internal/domain/entity"
 	"github.com/DementevVV/commitsum/internal/infrastructure/cache"
 	"github.com/DementevVV/commitsum/internal/infrastructure/clipboard"
 	"github.com/DementevVV/commitsum/internal/infrastructure/config"
 	"github.com/DementevVV/commitsum/internal/infrastructure/github"
+	"github.com/DementevVV/commitsum/internal/infrastructure/localgit"
 	"github.com/DementevVV/commitsum/internal/infrastructure/logger"
+	"github.com/DementevVV/commitsum/internal/infrastructure/seen"
 	"github.com/DementevVV/commitsum/internal/ui"
 	"github.com/DementevVV/commitsum/internal/usecase"
 )
@@ -26,6 +34,34 @@ var (
 )
 
 func main() {
+	os.Exit(run())
+}
+
+// run executes the application and returns the process exit code.
+func run() int {
+	listRepos := flag.Bool("list-repos", false, "print repositories with commits as JSON and exit")
+	rangeKey := flag.String("range", "today", "date range preset for non-interactive modes: today, yesterday, week, month or a custom preset key such as days-3")
+	since := flag.String("since", "", `start of the range, e.g. 2026-01-05, "last monday", "3 days ago", "last week" (overrides --range)`)
+	until := flag.String("until", "", `end of the range, e.g. today, yesterday, "last week" (default today)`)
+	stdinRange := flag.Bool("stdin-range", false, `read a range ("2024-03-01..2024-03-07" or a single date) from stdin, print the export and exit`)
+	format := flag.String("format", "text", "export format for --stdin-range: text, markdown, json, heatmap, pr-body, github-tasks, daily, legacy-json, compact")
+	skipIfUnchanged := flag.Bool("skip-if-unchanged", false, "with --stdin-range, print nothing and exit 4 when the report matches the previous run for the same range and format")
+	printChecksum := flag.Bool("print-checksum", false, "with --stdin-range, write the report checksum to stderr")
+	configPath := flag.String("config", "", "use this config file instead of ~/.config/commitsum/config.json")
+	cacheTTL := flag.String("cache-ttl", "", "override how long fetched commits are cached (e.g. 1h, 30m)")
+	flag.Usage = usage
+	flag.Parse()
+
+	var cacheTTLOverride time.Duration
+	if *cacheTTL != "" {
+		d, err := time.ParseDuration(*cacheTTL)
+		if err != nil || d <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: invalid --cache-ttl %q: expected a positive duration like 1h or 30m\n", *cacheTTL)
+			return exitUsage
+		}
+		cacheTTLOverride = d
+	}
+
 	// Initialize logging.
 	logLevel := logger.LevelInfo
 	if os.Getenv("DEBUG") != "" {
@@ -42,31 +78,141 @@ func main() {
 		}
 	}()
 
+	// Cancel in-flight requests on SIGINT/SIGTERM so deferred cleanup runs.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Load configuration.
+	if *configPath != "" {
+		config.SetPath(*configPath)
+		if _, err := os.ReadFile(*configPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: cannot read config %s, using defaults: %v\n", *configPath, err)
+		}
+	}
+	firstRun := !config.Exists()
 	cfg := config.Load()
+	entity.DisplayDateFormat = cfg.DisplayDateFormat
+	entity.DayCutoffHour = cfg.DayCutoffHour
+	for _, preset := range cfg.CustomPresets {
+		entity.AddDayPreset(preset.Label, preset.Days)
+	}
+
+	// Resolve the date range for non-interactive modes (and a preselected
+	// range for the TUI when --since/--until are given).
+	customRange := *since != "" || *until != ""
+	var dateRange entity.DateRange
+	var err error
+	if *skipIfUnchanged && !*stdinRange {
+		err = fmt.Errorf("--skip-if-unchanged requires --stdin-range")
+	} else if *printChecksum && !*stdinRange {
+		err = fmt.Errorf("--print-checksum requires --stdin-range")
+	} else if *stdinRange {
+		if !isStdinFormat(*format) {
+			err = fmt.Errorf("unknown format %q", *format)
+		} else {
+			dateRange, err = readStdinRange(os.Stdin)
+		}
+	} else if customRange {
+		dateRange, err = resolveSinceUntil(*since, *until)
+	} else if *listRepos {
+		dateRange, err = resolvePreset(*rangeKey)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitUsage
+	}
 
 	// Initialize infrastructure dependencies.
 	githubClient := github.NewClient()
+	githubClient.SetContext(ctx)
+	githubClient.SetDropEmptyMessages(cfg.DropEmptyMessages)
+	githubClient.SetConcurrency(cfg.APIConcurrency)
 	commitsCache, err := cache.NewCommitsCache()
 	if err != nil {
 		logger.Warn("Failed to initialize cache", "error", err.Error())
 	}
+	seenStore, err := seen.NewFileStore()
+	if err != nil {
+		logger.Warn("Failed to initialize seen commits store", "error", err.Error())
+	}
 	clipboardService := clipboard.New()
+	clipboardService.SetRetries(cfg.ClipboardRetries)
 
 	// Initialize use cases.
 	commitUC := usecase.NewCommitUseCase(githubClient, commitsCache)
+	commitUC.SetIncludeCoauthored(cfg.IncludeCoauthored)
+	commitUC.SetDateField(entity.DateField(cfg.DateField))
+	commitUC.SetOwnedOrgs(cfg.OwnedOrgs)
+	commitUC.SetCommitOrder(entity.CommitOrder(cfg.CommitOrder))
+	commitUC.SetCacheTTL(cacheTTLOverride)
+	commitUC.SetBranch(cfg.Branch)
+	commitUC.SetPullRequests(cfg.GroupByPR)
+	commitUC.SetWeekdays(parseWeekdays(cfg.Weekdays))
+	commitUC.SetEarliestDate(cfg.EarliestDate)
+	commitUC.SetExcludeReverts(cfg.ExcludeReverts)
+	commitUC.SetValidateCache(cfg.ValidateTodayCache)
+	if seenStore != nil {
+		commitUC.SetSeenStore(seenStore)
+	}
+	if cfg.IncludeLocalCommits {
+		commitUC.SetLocalRepos(localgit.NewClient(), cfg.LocalRepos)
+	}
 	exportUC := usecase.NewExportUseCase()
+	exportUC.SetFilenameDateFormat(cfg.FilenameDateFormat)
+	exportUC.SetPRBodyTemplate(cfg.PRBodyTemplate)
+	exportUC.SetPRBodyFile(cfg.PRBodyFile)
+	exportUC.SetPostProcessCommand(cfg.PostProcessCommand)
+	exportUC.SetVersion(Version)
+	exportUC.SetTemplates(cfg.Templates)
+	exportUC.SetRepoOrder(entity.RepoSortOrder(cfg.ExportRepoOrder))
+	exportUC.SetFileEncoding(cfg.ExportLineEnding, cfg.ExportBOM)
+	if cfg.GroupByOwnership {
+		exportUC.SetRepoGrouper(commitUC.GroupByOwnership)
+	}
+
+	// Non-interactive modes.
+	if *listRepos || *stdinRange {
+		var code int
+		if *stdinRange {
+			code = runExport(commitUC, exportUC, dateRange, entity.ExportFormat(*format), cfg.ExportOptions(), *skipIfUnchanged, *printChecksum)
+		} else {
+			code = runListRepos(commitUC, dateRange)
+		}
+		if ctx.Err() != nil {
+			logger.Warn("Interrupted by signal")
+			return exitInterrupted
+		}
+		return code
+	}
 
 	// Initialize TUI model.
 	model := ui.NewModel(cfg, commitUC, exportUC, clipboardService)
+	model.SetBuildInfo(Version, BuildTime)
+	model.SetLoginCommand(github.LoginCommand)
+	if customRange {
+		model.SetInitialRange(dateRange.StartDate, dateRange.EndDate)
+	} else if firstRun {
+		model.StartWizard()
+	}
 
 	// Run the application.
 	p := tea.NewProgram(model)
 	if _, err := p.Run(); err != nil {
 		logger.Error("Application error", "error", err.Error())
 		fmt.Printf("Error: %v\n", err)
-		os.Exit(1)
+		return exitError
+	}
+	if msg := model.ExitMessage(); msg != "" {
+		fmt.Fprintln(os.Stderr, msg)
+	}
+
+	// Purely local: counters from this session, never sent anywhere.
+	if cfg.SessionSummary || os.Getenv("COMMITSUM_SESSION_SUMMARY") != "" {
+		summary := model.SessionSummary()
+		logger.Info("Session summary", "summary", summary)
+		fmt.Fprintln(os.Stderr, summary)
 	}
 
 	logger.Info("Application terminated successfully")
+	return exitOK
 }
@@ -1,10 +1,139 @@
 // Package entity contains the core domain entities.
 package entity
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 // Commit represents a repository commit with its message.
 type Commit struct {
 	Repository string
 	Message    string
+	SHA        string
+	// Date is the commit timestamp for the queried date field (zero if unknown).
+	Date time.Time
+	// Coauthored marks commits that credit the user only via a Co-authored-by trailer.
+	Coauthored bool
+	// Local marks commits read from a local git checkout (possibly unpushed).
+	Local bool
+	// PR is the pull request that merged the commit, when known.
+	PR *PullRequest
+}
+
+// PullRequest identifies the pull request a commit was merged through.
+type PullRequest struct {
+	Number int
+	Title  string
+}
+
+// Heading returns the PR heading used in summaries, e.g. "#42 Add login".
+func (pr PullRequest) Heading() string {
+	return fmt.Sprintf("#%d %s", pr.Number, pr.Title)
+}
+
+// PRGroup is a run of commits merged through the same pull request. PR is
+// nil for commits without one.
+type PRGroup struct {
+	PR      *PullRequest
+	Commits []Commit
+}
+
+// GroupByPR groups commits under their pull requests in order of first
+// appearance, with commits without a PR last. It returns nil when no commit
+// has PR data, so callers can fall back to a flat list.
+func GroupByPR(commits []Commit) []PRGroup {
+	var groups []PRGroup
+	index := make(map[int]int)
+	var loose []Commit
+	for _, commit := range commits {
+		if commit.PR == nil {
+			loose = append(loose, commit)
+			continue
+		}
+		i, ok := index[commit.PR.Number]
+		if !ok {
+			i = len(groups)
+			index[commit.PR.Number] = i
+			groups = append(groups, PRGroup{PR: commit.PR})
+		}
+		groups[i].Commits = append(groups[i].Commits, commit)
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+	if len(loose) > 0 {
+		groups = append(groups, PRGroup{Commits: loose})
+	}
+	return groups
+}
+
+// ShortSHA returns the 7-character abbreviated SHA, or "" when unknown.
+func (c Commit) ShortSHA() string {
+	if len(c.SHA) > 7 {
+		return c.SHA[:7]
+	}
+	return c.SHA
+}
+
+// IsRevert reports whether the commit reverts another one, as created by
+// git revert ("Revert \"...\"").
+func (c Commit) IsRevert() bool {
+	return strings.HasPrefix(c.Message, `Revert "`)
+}
+
+// mergePrefixes are the headlines git and GitHub generate for merge commits.
+var mergePrefixes = []string{
+	"Merge pull request ",
+	"Merge branch ",
+	"Merge remote-tracking branch ",
+	"Merge tag ",
+}
+
+// IsMerge reports whether the commit's headline looks like a generated merge
+// commit ("Merge pull request #1 ...", "Merge branch 'main' ...").
+func (c Commit) IsMerge() bool {
+	for _, prefix := range mergePrefixes {
+		if strings.HasPrefix(c.Message, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// CommitOrder controls how commits are ordered within a repository.
+type CommitOrder string
+
+const (
+	CommitOrderAPI      CommitOrder = "api"
+	CommitOrderMessage  CommitOrder = "message"
+	CommitOrderTimeAsc  CommitOrder = "time-asc"
+	CommitOrderTimeDesc CommitOrder = "time-desc"
+)
+
+// RepoSortOrder controls how repositories are ordered in lists.
+type RepoSortOrder string
+
+const (
+	RepoSortName  RepoSortOrder = "name"
+	RepoSortCount RepoSortOrder = "count"
+	RepoSortOwner RepoSortOrder = "owner"
+)
+
+// RepoSortOrders lists the available orders in cycling order.
+var RepoSortOrders = []RepoSortOrder{RepoSortName, RepoSortCount, RepoSortOwner}
+
+// Label returns a short human-readable name for the sort order.
+func (o RepoSortOrder) Label() string {
+	switch o {
+	case RepoSortCount:
+		return "commit count"
+	case RepoSortOwner:
+		return "owner/name"
+	default:
+		return "name"
+	}
 }
 
 // CommitData represents commits grouped by repository.
@@ -12,4 +141,24 @@ type CommitData struct {
 	Commits  map[string][]Commit
 	RepoList []string
 	Warning  string
+	// ExcludedByBranch counts commits dropped because they are not on the configured branch.
+	ExcludedByBranch int
+	// SearchTotal is how many commits the author search returned, before any
+	// filtering or merging. Used to validate cached entries.
+	SearchTotal int
+	// ExcludedByWeekday counts commits dropped by the weekday filter.
+	ExcludedByWeekday int
+	// DroppedEmpty counts commits dropped for having no message (drop_empty_messages).
+	DroppedEmpty int
+	// FetchedAt is when the data was fetched from GitHub; older than now when served from cache.
+	FetchedAt time.Time
+	// Capped is set when the search hit GitHub's result limit, so commits
+	// may be missing.
+	Capped bool
+}
+
+// RepoGroup is a titled set of repositories within a summary.
+type RepoGroup struct {
+	Title string
+	Repos []string
 }
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGroupByPR(t *testing.T) {
+	login := &PullRequest{Number: 42, Title: "Login"}
+	header := &PullRequest{Number: 7, Title: "Header"}
+	commit := func(sha string, pr *PullRequest) Commit {
+		return Commit{SHA: sha, PR: pr}
+	}
+
+	tests := []struct {
+		name    string
+		commits []Commit
+		want    []string
+	}{
+		{
+			name:    "no PR data",
+			commits: []Commit{commit("a", nil), commit("b", nil)},
+		},
+		{
+			name:    "grouped in order of first appearance",
+			commits: []Commit{commit("a", header), commit("b", login), commit("c", header)},
+			want:    []string{"#7 Header: a c", "#42 Login: b"},
+		},
+		{
+			name:    "commits without a PR last",
+			commits: []Commit{commit("a", nil), commit("b", login), commit("c", nil)},
+			want:    []string{"#42 Login: b", "none: a c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, g := range GroupByPR(tt.commits) {
+				heading := "none"
+				if g.PR != nil {
+					heading = g.PR.Heading()
+				}
+				var shas []string
+				for _, c := range g.Commits {
+					shas = append(shas, c.SHA)
+				}
+				got = append(got, heading+": "+strings.Join(shas, " "))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("GroupByPR() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package entity
+
+// RepoComparison holds commit counts for one repository on both sides of a comparison.
+type RepoComparison struct {
+	Repository string `json:"repository"`
+	Left       int    `json:"left"`
+	Right      int    `json:"right"`
+	// Delta is Right minus Left.
+	Delta int `json:"delta"`
+}
+
+// Comparison is a per-repository comparison of two commit sets, such as two
+// users over the same range or one user over two ranges.
+type Comparison struct {
+	LeftLabel  string           `json:"left_label"`
+	RightLabel string           `json:"right_label"`
+	DateRange  string           `json:"date_range,omitempty"`
+	LeftTotal  int              `json:"left_total"`
+	RightTotal int              `json:"right_total"`
+	Repos      []RepoComparison `json:"repos"`
+	// OnlyLeft, OnlyRight and Both list repositories by which side has commits.
+	OnlyLeft  []string `json:"only_left"`
+	OnlyRight []string `json:"only_right"`
+	Both      []string `json:"both"`
+}
@@ -5,6 +5,55 @@ import (
 	"time"
 )
 
+// DateLayout is the canonical date layout used for input and queries.
+const DateLayout = "2006-01-02"
+
+// DisplayDateFormat is the layout used when showing dates to the user.
+var DisplayDateFormat = DateLayout
+
+// RangeSeparator joins the start and end of a displayed date range.
+const RangeSeparator = "→"
+
+// DayCutoffHour is the local hour at which a new day starts, for people who
+// work past midnight. With a cutoff of 4, commits made at 1am count towards
+// the previous day's presets. Zero means midnight.
+var DayCutoffHour int
+
+// effectiveNow returns the current local time shifted back by DayCutoffHour.
+func effectiveNow() time.Time {
+	return time.Now().Add(-time.Duration(DayCutoffHour) * time.Hour)
+}
+
+// Today returns the current day as YYYY-MM-DD, honoring DayCutoffHour.
+func Today() string {
+	return effectiveNow().Format(DateLayout)
+}
+
+// IsFutureDate reports whether a date lies after the current calendar day.
+// The cutoff only ever moves "today" backwards, so the calendar day remains
+// the limit and late-night sessions are never blocked.
+func IsFutureDate(date time.Time) bool {
+	now := time.Now()
+	endOfToday := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
+	return date.After(endOfToday)
+}
+
+// DateField selects which commit date a range query matches against.
+type DateField string
+
+const (
+	DateFieldCommitter DateField = "committer-date"
+	DateFieldAuthor    DateField = "author-date"
+)
+
+// Label returns a short human-readable name for the date field.
+func (f DateField) Label() string {
+	if f == DateFieldAuthor {
+		return "author date"
+	}
+	return "committer date"
+}
+
 // DateRange represents a date range selection.
 type DateRange struct {
 	StartDate string
@@ -16,6 +65,9 @@ type DateRange struct {
 type DateRangePreset struct {
 	Key   string
 	Label string
+	// Days, when positive, makes this a "last N days" preset resolved by
+	// GetDateRange like the built-in week and month presets.
+	Days int
 }
 
 // DateRangePresets contains available date range presets.
@@ -27,10 +79,46 @@ var DateRangePresets = []DateRangePreset{
 	{Key: "custom", Label: "Custom date"},
 }
 
+// AddDayPreset registers a "last N days" preset, inserted before the custom
+// entry so that custom date stays last. It returns the new preset's key.
+func AddDayPreset(label string, days int) string {
+	key := fmt.Sprintf("days-%d", days)
+	if label == "" {
+		label = fmt.Sprintf("Last %d days", days)
+	}
+	preset := DateRangePreset{Key: key, Label: label, Days: days}
+
+	for i, p := range DateRangePresets {
+		if p.Key == key {
+			DateRangePresets[i] = preset
+			return key
+		}
+	}
+	i := len(DateRangePresets) - 1
+	DateRangePresets = append(DateRangePresets[:i:i], append([]DateRangePreset{preset}, DateRangePresets[i:]...)...)
+	return key
+}
+
+// ExcludeToday moves the end of a multi-day range ending today back to
+// yesterday, so in-progress work is left out. Other ranges are unchanged.
+func ExcludeToday(dr DateRange) DateRange {
+	today := Today()
+	if dr.StartDate == dr.EndDate || dr.EndDate != today {
+		return dr
+	}
+	end, err := time.Parse(DateLayout, today)
+	if err != nil {
+		return dr
+	}
+	dr.EndDate = end.AddDate(0, 0, -1).Format(DateLayout)
+	dr.Label += ", excluding today"
+	return dr
+}
+
 // GetDateRange returns start and end dates for a preset.
 func GetDateRange(preset string) DateRange {
-	now := time.Now()
-	today := now.Format("2006-01-02")
+	now := effectiveNow()
+	today := now.Format(DateLayout)
 
 	switch preset {
 	case "today":
@@ -61,6 +149,15 @@ func GetDateRange(preset string) DateRange {
 			Label:     "Last 30 days",
 		}
 	default:
+		for _, p := range DateRangePresets {
+			if p.Key == preset && p.Days > 0 {
+				return DateRange{
+					StartDate: now.AddDate(0, 0, -p.Days).Format(DateLayout),
+					EndDate:   today,
+					Label:     p.Label,
+				}
+			}
+		}
 		return DateRange{
 			StartDate: today,
 			EndDate:   today,
@@ -69,10 +166,42 @@ func GetDateRange(preset string) DateRange {
 	}
 }
 
+// RangeDays returns how many calendar days a YYYY-MM-DD range spans,
+// inclusive, or 0 if either date is invalid.
+func RangeDays(startDate, endDate string) int {
+	start, err := time.Parse(DateLayout, startDate)
+	if err != nil {
+		return 0
+	}
+	end, err := time.Parse(DateLayout, endDate)
+	if err != nil {
+		return 0
+	}
+	return int(end.Sub(start).Hours()/24) + 1
+}
+
 // FormatDateDisplay formats date for display.
 func FormatDateDisplay(startDate, endDate string) string {
+	return FormatDateRange(startDate, endDate, RangeSeparator)
+}
+
+// FormatDateRange formats date for display, joining a range with separator.
+func FormatDateRange(startDate, endDate, separator string) string {
 	if startDate == endDate {
-		return startDate
+		return FormatDate(startDate, DisplayDateFormat)
+	}
+	return fmt.Sprintf("%s %s %s", FormatDate(startDate, DisplayDateFormat), separator, FormatDate(endDate, DisplayDateFormat))
+}
+
+// FormatDate reformats a YYYY-MM-DD date using layout, returning the input
+// unchanged when it cannot be parsed.
+func FormatDate(date, layout string) string {
+	if layout == "" || layout == DateLayout {
+		return date
+	}
+	t, err := time.Parse(DateLayout, date)
+	if err != nil {
+		return date
 	}
-	return fmt.Sprintf("%s → %s", startDate, endDate)
+	return t.Format(layout)
 }
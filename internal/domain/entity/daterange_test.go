@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"slices"
+	"testing"
+)
+
+// restorePresets puts DateRangePresets back after the test.
+func restorePresets(t *testing.T) {
+	t.Helper()
+	saved := slices.Clone(DateRangePresets)
+	t.Cleanup(func() { DateRangePresets = saved })
+}
+
+func TestAddDayPreset(t *testing.T) {
+	restorePresets(t)
+
+	key := AddDayPreset("Last 3 days", 3)
+	if key != "days-3" {
+		t.Fatalf("key = %q, want days-3", key)
+	}
+
+	today := effectiveNow()
+	want := DateRange{
+		StartDate: today.AddDate(0, 0, -3).Format(DateLayout),
+		EndDate:   today.Format(DateLayout),
+		Label:     "Last 3 days",
+	}
+	if got := GetDateRange(key); got != want {
+		t.Errorf("GetDateRange(%q) = %+v, want %+v", key, got, want)
+	}
+
+	var keys []string
+	for _, p := range DateRangePresets {
+		keys = append(keys, p.Key)
+	}
+	if want := []string{"today", "yesterday", "week", "month", "days-3", "custom"}; !slices.Equal(keys, want) {
+		t.Errorf("preset keys = %v, want %v", keys, want)
+	}
+}
+
+func TestAddDayPresetDefaultsAndReplaces(t *testing.T) {
+	restorePresets(t)
+	before := len(DateRangePresets)
+
+	AddDayPreset("", 14)
+	AddDayPreset("Sprint", 14)
+
+	if len(DateRangePresets) != before+1 {
+		t.Fatalf("got %d presets, want %d: re-adding a day count should replace it", len(DateRangePresets), before+1)
+	}
+	if got := GetDateRange("days-14").Label; got != "Sprint" {
+		t.Errorf("label = %q, want Sprint", got)
+	}
+
+	AddDayPreset("", 5)
+	if got := GetDateRange("days-5").Label; got != "Last 5 days" {
+		t.Errorf("default label = %q, want %q", got, "Last 5 days")
+	}
+}
@@ -1,31 +1,116 @@
 package entity
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
 
 // ExportFormat represents the output format type.
 type ExportFormat string
 
 const (
-	FormatText     ExportFormat = "text"
-	FormatMarkdown ExportFormat = "markdown"
-	FormatJSON     ExportFormat = "json"
+	FormatText        ExportFormat = "text"
+	FormatMarkdown    ExportFormat = "markdown"
+	FormatJSON        ExportFormat = "json"
+	FormatHeatmap     ExportFormat = "heatmap"
+	FormatPRBody      ExportFormat = "pr-body"
+	FormatGitHubTasks ExportFormat = "github-tasks"
+	FormatDaily       ExportFormat = "daily"
+	FormatCompact     ExportFormat = "compact"
+	// FormatLegacyJSON is a compatibility format matching the raw GitHub
+	// search response the pre-refactor tool emitted.
+	FormatLegacyJSON ExportFormat = "legacy-json"
 )
 
+// ExportOptions holds the presentation toggles applied by exporters.
+type ExportOptions struct {
+	// IncludeStats adds the statistics section to formats that have one.
+	IncludeStats bool
+	// Footer appends the "Generated by" provenance line.
+	Footer bool
+	// CollapsibleMarkdown wraps each repository in a <details> block.
+	CollapsibleMarkdown bool
+	// MessageCase normalizes commit headline casing.
+	MessageCase MessageCase
+	// ShowSHA prefixes commit lines with the short SHA, when known.
+	ShowSHA bool
+	// RepoAliases maps owner/name to a friendly label shown next to the name.
+	RepoAliases map[string]string
+	// Bullet is the marker before each commit line (empty = "-").
+	Bullet string
+	// Watchlist lists repositories reported under "No activity" when they
+	// have no commits.
+	Watchlist []string
+	// GroupByPR nests commits under their pull request headings when PR
+	// data is available.
+	GroupByPR bool
+	// Deterministic sorts commits by date, message and SHA and leaves out
+	// the generation time, so identical commits render to identical bytes.
+	Deterministic bool
+}
+
+// BulletMarker returns the commit line marker, "-" unless Bullet is set.
+func (o ExportOptions) BulletMarker() string {
+	if o.Bullet == "" {
+		return "-"
+	}
+	return o.Bullet
+}
+
+// DefaultExportOptions returns the options used without configuration.
+func DefaultExportOptions() ExportOptions {
+	return ExportOptions{
+		IncludeStats: true,
+		Footer:       true,
+		MessageCase:  MessageCaseNone,
+	}
+}
+
 // CommitExport represents a commit for export.
 type CommitExport struct {
 	Repository string `json:"repository"`
+	Alias      string `json:"alias,omitempty"`
 	Message    string `json:"message"`
+	SHA        string `json:"sha,omitempty"`
+	Coauthored bool   `json:"coauthored,omitempty"`
+	Local      bool   `json:"local,omitempty"`
+	Revert     bool   `json:"revert,omitempty"`
+}
+
+// LegacyExport is the {items: [{repository: {full_name}, commit: {message}}]}
+// shape of the old APIResponse, kept for scripts written against it.
+type LegacyExport struct {
+	Items []LegacyItem `json:"items"`
+}
+
+// LegacyItem is one commit in a LegacyExport.
+type LegacyItem struct {
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Commit struct {
+		Message string `json:"message"`
+	} `json:"commit"`
 }
 
 // SummaryExport represents the full summary for export.
 type SummaryExport struct {
-	Date         string                    `json:"date"`
-	DateRange    string                    `json:"date_range,omitempty"`
-	TotalRepos   int                       `json:"total_repos"`
-	TotalCommits int                       `json:"total_commits"`
-	Commits      map[string][]CommitExport `json:"commits"`
-	Stats        *Statistics               `json:"stats,omitempty"`
-	GeneratedAt  string                    `json:"generated_at"`
+	Subject         string                    `json:"subject"`
+	Date            string                    `json:"date"`
+	DateRange       string                    `json:"date_range,omitempty"`
+	TotalRepos      int                       `json:"total_repos"`
+	TotalCommits    int                       `json:"total_commits"`
+	Commits         map[string][]CommitExport `json:"commits"`
+	Groups          map[string][]string       `json:"groups,omitempty"`
+	NoActivity      []string                  `json:"no_activity,omitempty"`
+	Stats           *Statistics               `json:"stats,omitempty"`
+	ContentChecksum string                    `json:"checksum"`
+	GeneratedAt     string                    `json:"generated_at,omitempty"`
 }
 
 // NewSummaryExport creates a new SummaryExport instance.
@@ -36,3 +121,62 @@ func NewSummaryExport(dateStr string) *SummaryExport {
 		GeneratedAt: time.Now().Format(time.RFC3339),
 	}
 }
+
+// subjectASCII transliterates the punctuation used in date labels to ASCII.
+var subjectASCII = strings.NewReplacer("→", "->", "—", "-", "–", "-", "…", "...")
+
+// SubjectLine returns a one-line digest subject such as
+// "5 commits across 3 repos - 2026-02-02". It is plain ASCII so it can be
+// used as an email subject header: known punctuation is transliterated and
+// any other non-ASCII rune becomes a space.
+func SubjectLine(totalCommits, totalRepos int, dateStr string) string {
+	line := fmt.Sprintf("%d %s across %d %s - %s",
+		totalCommits, plural(totalCommits, "commit", "commits"),
+		totalRepos, plural(totalRepos, "repo", "repos"), subjectASCII.Replace(dateStr))
+	line = strings.Map(func(r rune) rune {
+		if r < ' ' || r > '~' {
+			return ' '
+		}
+		return r
+	}, line)
+	return strings.Join(strings.Fields(line), " ")
+}
+
+// SubjectLine returns the digest subject for this export.
+func (s *SummaryExport) SubjectLine() string {
+	return SubjectLine(s.TotalCommits, s.TotalRepos, s.Date)
+}
+
+// Checksum returns a stable SHA-256 over the exported commits (repository,
+// SHA and message), independent of map order, generation time and stats.
+// Equal checksums mean the report content did not change.
+func (s *SummaryExport) Checksum() string {
+	repos := make([]string, 0, len(s.Commits))
+	for repo := range s.Commits {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	h := sha256.New()
+	for _, repo := range repos {
+		commits := slices.Clone(s.Commits[repo])
+		sort.SliceStable(commits, func(i, j int) bool {
+			if commits[i].SHA != commits[j].SHA {
+				return commits[i].SHA < commits[j].SHA
+			}
+			return commits[i].Message < commits[j].Message
+		})
+		for _, commit := range commits {
+			fmt.Fprintf(h, "%s\x00%s\x00%s\n", repo, commit.SHA, commit.Message)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// plural picks the singular or plural form for n.
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
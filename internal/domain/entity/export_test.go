@@ -0,0 +1,28 @@
+package entity
+
+import "testing"
+
+func TestSubjectLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		commits int
+		repos   int
+		dateStr string
+		want    string
+	}{
+		{name: "single day", commits: 5, repos: 3, dateStr: "2026-02-02", want: "5 commits across 3 repos - 2026-02-02"},
+		{name: "singular", commits: 1, repos: 1, dateStr: "2026-02-02", want: "1 commit across 1 repo - 2026-02-02"},
+		{name: "range arrow", commits: 2, repos: 1, dateStr: "2026-02-01 → 2026-02-07", want: "2 commits across 1 repo - 2026-02-01 -> 2026-02-07"},
+		{name: "em dash", commits: 2, repos: 1, dateStr: "Week — 2026-02-02", want: "2 commits across 1 repo - Week - 2026-02-02"},
+		{name: "other non-ASCII", commits: 2, repos: 1, dateStr: "Févr. ✨ 2", want: "2 commits across 1 repo - F vr. 2"},
+		{name: "control characters", commits: 2, repos: 1, dateStr: "2026-02-02\nBcc: x", want: "2 commits across 1 repo - 2026-02-02 Bcc: x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SubjectLine(tt.commits, tt.repos, tt.dateStr); got != tt.want {
+				t.Errorf("SubjectLine(%d, %d, %q) = %q, want %q", tt.commits, tt.repos, tt.dateStr, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,4 @@
+package entity
+
+// Heatmap counts commits by weekday (Sunday first) and hour of day.
+type Heatmap [7][24]int
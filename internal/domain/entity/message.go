@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MessageCase selects how commit headlines are cased in views and exports.
+type MessageCase string
+
+const (
+	MessageCaseNone     MessageCase = "none"
+	MessageCaseSentence MessageCase = "sentence"
+	MessageCaseLower    MessageCase = "lower"
+	MessageCaseTitle    MessageCase = "title"
+)
+
+// Apply returns the message with the casing applied. Sentence and title case
+// only uppercase leading letters and leave the rest alone, so acronyms such
+// as "API" survive.
+func (mc MessageCase) Apply(message string) string {
+	switch mc {
+	case MessageCaseSentence:
+		return upperFirstLetter(message)
+	case MessageCaseLower:
+		return strings.ToLower(message)
+	case MessageCaseTitle:
+		words := strings.Split(message, " ")
+		for i, word := range words {
+			words[i] = upperFirstLetter(word)
+		}
+		return strings.Join(words, " ")
+	default:
+		return message
+	}
+}
+
+// upperFirstLetter uppercases the first letter of s. Leading whitespace,
+// emoji and symbols are skipped; if s then starts with anything other than a
+// letter, such as a digit or a bracket, it is returned unchanged.
+func upperFirstLetter(s string) string {
+	for i, r := range s {
+		if unicode.IsSpace(r) || unicode.IsSymbol(r) || unicode.In(r, unicode.Mn, unicode.Cf) {
+			continue
+		}
+		if !unicode.IsLetter(r) || unicode.IsUpper(r) {
+			return s
+		}
+		return s[:i] + string(unicode.ToUpper(r)) + s[i+utf8.RuneLen(r):]
+	}
+	return s
+}
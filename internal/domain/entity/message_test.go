@@ -0,0 +1,43 @@
+package entity
+
+import "testing"
+
+func TestMessageCaseApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    MessageCase
+		message string
+		want    string
+	}{
+		{name: "none keeps input", mode: MessageCaseNone, message: "fix API bug", want: "fix API bug"},
+		{name: "unknown keeps input", mode: "shout", message: "fix API bug", want: "fix API bug"},
+
+		{name: "sentence", mode: MessageCaseSentence, message: "fix API bug", want: "Fix API bug"},
+		{name: "sentence keeps acronyms", mode: MessageCaseSentence, message: "bump HTTP client in CLI", want: "Bump HTTP client in CLI"},
+		{name: "sentence already upper", mode: MessageCaseSentence, message: "Fix bug", want: "Fix bug"},
+		{name: "sentence keeps bracket tag", mode: MessageCaseSentence, message: "[ci] skip lint", want: "[ci] skip lint"},
+		{name: "sentence keeps leading digit", mode: MessageCaseSentence, message: "2fa support", want: "2fa support"},
+		{name: "sentence after emoji", mode: MessageCaseSentence, message: "🐛 fix crash", want: "🐛 Fix crash"},
+		{name: "sentence after emoji with selector", mode: MessageCaseSentence, message: "⚠️ drop support", want: "⚠️ Drop support"},
+		{name: "sentence after leading space", mode: MessageCaseSentence, message: "  fix crash", want: "  Fix crash"},
+		{name: "sentence non-ASCII letter", mode: MessageCaseSentence, message: "éviter le crash", want: "Éviter le crash"},
+		{name: "sentence no letters", mode: MessageCaseSentence, message: "🚀 1.2.3", want: "🚀 1.2.3"},
+		{name: "sentence empty", mode: MessageCaseSentence, message: "", want: ""},
+
+		{name: "lower", mode: MessageCaseLower, message: "Fix API Bug", want: "fix api bug"},
+		{name: "lower emoji", mode: MessageCaseLower, message: "✨ Add Ünicode", want: "✨ add ünicode"},
+
+		{name: "title", mode: MessageCaseTitle, message: "fix the API bug", want: "Fix The API Bug"},
+		{name: "title after emoji", mode: MessageCaseTitle, message: "✨ add feature", want: "✨ Add Feature"},
+		{name: "title keeps spacing", mode: MessageCaseTitle, message: "a  b", want: "A  B"},
+		{name: "title punctuation", mode: MessageCaseTitle, message: "(scope) add x", want: "(scope) Add X"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.mode.Apply(tt.message); got != tt.want {
+				t.Errorf("%s.Apply(%q) = %q, want %q", tt.mode, tt.message, got, tt.want)
+			}
+		})
+	}
+}
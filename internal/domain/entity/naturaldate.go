@@ -0,0 +1,114 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseNaturalDate resolves a date expression to YYYY-MM-DD relative to the
+// current day (honoring DayCutoffHour). Besides ISO dates it understands
+// "today", "yesterday", "N days ago", "last monday" (any weekday), "this
+// week" and "last week". Week expressions resolve to their Monday, or, when
+// end is set, to the last day of that week (never later than today).
+func ParseNaturalDate(input string, end bool) (string, error) {
+	return parseNaturalDate(input, effectiveNow(), end)
+}
+
+// ParseRange parses a "start..end" range or a single date. Either side
+// may be any expression understood by ParseNaturalDate.
+func ParseRange(line string) (DateRange, error) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return DateRange{}, fmt.Errorf("empty range, expected START..END or a single date")
+	}
+	since, until, found := strings.Cut(line, "..")
+	if !found {
+		until = since
+	}
+	since, until = strings.TrimSpace(since), strings.TrimSpace(until)
+	if since == "" || until == "" || strings.Contains(until, "..") {
+		return DateRange{}, fmt.Errorf("malformed range %q, expected START..END or a single date", line)
+	}
+
+	start, err := ParseNaturalDate(since, false)
+	if err != nil {
+		return DateRange{}, fmt.Errorf("invalid range start: %w", err)
+	}
+	end, err := ParseNaturalDate(until, true)
+	if err != nil {
+		return DateRange{}, fmt.Errorf("invalid range end: %w", err)
+	}
+	if start > end {
+		return DateRange{}, fmt.Errorf("range start %s is after end %s", start, end)
+	}
+	return DateRange{StartDate: start, EndDate: end, Label: "Custom"}, nil
+}
+
+// parseNaturalDate implements ParseNaturalDate for a given now.
+func parseNaturalDate(input string, now time.Time, end bool) (string, error) {
+	expr := strings.Join(strings.Fields(strings.ToLower(input)), " ")
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	if t, err := time.Parse(DateLayout, expr); err == nil {
+		return t.Format(DateLayout), nil
+	}
+
+	switch expr {
+	case "today":
+		return today.Format(DateLayout), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1).Format(DateLayout), nil
+	case "this week":
+		if end {
+			return today.Format(DateLayout), nil
+		}
+		return weekStart(today).Format(DateLayout), nil
+	case "last week":
+		monday := weekStart(today).AddDate(0, 0, -7)
+		if end {
+			return monday.AddDate(0, 0, 6).Format(DateLayout), nil
+		}
+		return monday.Format(DateLayout), nil
+	}
+
+	if rest, ok := strings.CutPrefix(expr, "last "); ok {
+		if weekday, ok := ParseWeekday(rest); ok {
+			// The most recent such weekday strictly before today.
+			days := (int(today.Weekday()) - int(weekday) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			return today.AddDate(0, 0, -days).Format(DateLayout), nil
+		}
+	}
+
+	if rest, ok := strings.CutSuffix(expr, " ago"); ok {
+		fields := strings.Fields(rest)
+		if len(fields) == 2 && (fields[1] == "day" || fields[1] == "days") {
+			n, err := strconv.Atoi(fields[0])
+			if err == nil && n >= 0 {
+				return today.AddDate(0, 0, -n).Format(DateLayout), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("unrecognized date %q (use YYYY-MM-DD, today, yesterday, N days ago, last <weekday>, this week or last week)", input)
+}
+
+// weekStart returns the Monday of the week containing day.
+func weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// ParseWeekday parses a full English weekday name, ignoring case.
+func ParseWeekday(name string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), name) {
+			return d, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,114 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseNaturalDate(t *testing.T) {
+	// Wednesday afternoon; the week starts on Monday 2024-03-11.
+	wednesday := time.Date(2024, time.March, 13, 15, 0, 0, 0, time.UTC)
+	// Sunday, the last day of that week.
+	sunday := time.Date(2024, time.March, 17, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input   string
+		now     time.Time
+		end     bool
+		want    string
+		wantErr bool
+	}{
+		{input: "2024-02-29", now: wednesday, want: "2024-02-29"},
+		{input: " 2024-02-29 ", now: wednesday, end: true, want: "2024-02-29"},
+		{input: "today", now: wednesday, want: "2024-03-13"},
+		{input: "TODAY", now: wednesday, end: true, want: "2024-03-13"},
+		{input: "yesterday", now: wednesday, want: "2024-03-12"},
+		{input: "yesterday", now: time.Date(2024, time.March, 1, 0, 30, 0, 0, time.UTC), want: "2024-02-29"},
+		{input: "0 days ago", now: wednesday, want: "2024-03-13"},
+		{input: "1 day ago", now: wednesday, want: "2024-03-12"},
+		{input: "3 days ago", now: wednesday, want: "2024-03-10"},
+		{input: "3  Days   Ago", now: wednesday, want: "2024-03-10"},
+		{input: "30 days ago", now: wednesday, want: "2024-02-12"},
+		{input: "last monday", now: wednesday, want: "2024-03-11"},
+		{input: "last tuesday", now: wednesday, want: "2024-03-12"},
+		{input: "last wednesday", now: wednesday, want: "2024-03-06"},
+		{input: "last Thursday", now: wednesday, want: "2024-03-07"},
+		{input: "last sunday", now: wednesday, want: "2024-03-10"},
+		{input: "last sunday", now: sunday, want: "2024-03-10"},
+		{input: "this week", now: wednesday, want: "2024-03-11"},
+		{input: "this week", now: wednesday, end: true, want: "2024-03-13"},
+		{input: "this week", now: sunday, want: "2024-03-11"},
+		{input: "this week", now: sunday, end: true, want: "2024-03-17"},
+		{input: "last week", now: wednesday, want: "2024-03-04"},
+		{input: "last week", now: wednesday, end: true, want: "2024-03-10"},
+		{input: "last week", now: sunday, want: "2024-03-04"},
+		{input: "last week", now: sunday, end: true, want: "2024-03-10"},
+
+		{input: "", now: wednesday, wantErr: true},
+		{input: "tomorrow", now: wednesday, wantErr: true},
+		{input: "2024-02-30", now: wednesday, wantErr: true},
+		{input: "-1 days ago", now: wednesday, wantErr: true},
+		{input: "three days ago", now: wednesday, wantErr: true},
+		{input: "3 weeks ago", now: wednesday, wantErr: true},
+		{input: "last funday", now: wednesday, wantErr: true},
+		{input: "next monday", now: wednesday, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseNaturalDate(tt.input, tt.now, tt.end)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseNaturalDate(%q) = %q, want an error", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseNaturalDate(%q, %s, end=%v): %v", tt.input, tt.now.Weekday(), tt.end, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseNaturalDate(%q, %s, end=%v) = %q, want %q", tt.input, tt.now.Weekday(), tt.end, got, tt.want)
+		}
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    DateRange
+		wantErr string
+	}{
+		{name: "range", line: "2024-03-01..2024-03-07", want: DateRange{StartDate: "2024-03-01", EndDate: "2024-03-07", Label: "Custom"}},
+		{name: "single date", line: "2024-03-01", want: DateRange{StartDate: "2024-03-01", EndDate: "2024-03-01", Label: "Custom"}},
+		{name: "surrounding space", line: "  2024-03-01 .. 2024-03-07 \r", want: DateRange{StartDate: "2024-03-01", EndDate: "2024-03-07", Label: "Custom"}},
+		{name: "same day", line: "2024-03-01..2024-03-01", want: DateRange{StartDate: "2024-03-01", EndDate: "2024-03-01", Label: "Custom"}},
+		{name: "natural", line: "today..today", want: DateRange{StartDate: Today(), EndDate: Today(), Label: "Custom"}},
+		{name: "empty", line: "  ", wantErr: "empty range, expected START..END or a single date"},
+		{name: "missing end", line: "2024-03-01..", wantErr: `malformed range "2024-03-01..", expected START..END or a single date`},
+		{name: "missing start", line: "..2024-03-07", wantErr: `malformed range "..2024-03-07", expected START..END or a single date`},
+		{name: "three parts", line: "2024-03-01..2024-03-02..2024-03-03", wantErr: `malformed range "2024-03-01..2024-03-02..2024-03-03", expected START..END or a single date`},
+		{name: "bad start", line: "2024-13-01..2024-03-07", wantErr: "invalid range start"},
+		{name: "bad end", line: "2024-03-01..soon", wantErr: "invalid range end"},
+		{name: "reversed", line: "2024-03-07..2024-03-01", wantErr: "range start 2024-03-07 is after end 2024-03-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRange(tt.line)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseRange(%q) error = %v, want %q", tt.line, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRange(%q): %v", tt.line, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRange(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
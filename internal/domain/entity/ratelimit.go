@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// RateLimit is the GitHub search API quota for the current user.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// String renders the quota as "API: 27/30 remaining".
+func (r RateLimit) String() string {
+	return fmt.Sprintf("API: %d/%d remaining", r.Remaining, r.Limit)
+}
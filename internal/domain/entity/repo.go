@@ -0,0 +1,15 @@
+package entity
+
+import "strings"
+
+// SplitRepo splits a repository name into owner and name. Plain GitHub names
+// have exactly one slash; nested paths such as "group/subgroup/name" keep
+// everything before the last slash as the owner. A name without a slash has
+// no owner.
+func SplitRepo(full string) (owner, name string) {
+	i := strings.LastIndex(full, "/")
+	if i < 0 {
+		return "", full
+	}
+	return full[:i], full[i+1:]
+}
@@ -0,0 +1,28 @@
+package entity
+
+import "testing"
+
+func TestSplitRepo(t *testing.T) {
+	tests := []struct {
+		name      string
+		full      string
+		wantOwner string
+		wantName  string
+	}{
+		{name: "owner and name", full: "acme/api", wantOwner: "acme", wantName: "api"},
+		{name: "no slash", full: "api", wantOwner: "", wantName: "api"},
+		{name: "nested groups", full: "acme/platform/api", wantOwner: "acme/platform", wantName: "api"},
+		{name: "leading slash", full: "/api", wantOwner: "", wantName: "api"},
+		{name: "trailing slash", full: "acme/", wantOwner: "acme", wantName: ""},
+		{name: "empty", full: "", wantOwner: "", wantName: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner, name := SplitRepo(tt.full)
+			if owner != tt.wantOwner || name != tt.wantName {
+				t.Errorf("SplitRepo(%q) = (%q, %q), want (%q, %q)", tt.full, owner, name, tt.wantOwner, tt.wantName)
+			}
+		})
+	}
+}
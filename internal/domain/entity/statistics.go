@@ -7,4 +7,7 @@ type Statistics struct {
 	CommitsPerRepo    map[string]int `json:"commits_per_repo"`
 	MostActiveRepo    string         `json:"most_active_repo"`
 	MaxCommits        int            `json:"max_commits"`
+	// Reverts counts revert commits; they are left out of the other counts
+	// when exclude_reverts is set.
+	Reverts int `json:"reverts"`
 }
@@ -1,18 +1,29 @@
 package repository
 
-import "github.com/DementevVV/commitsum/internal/domain/entity"
+import (
+	"time"
+
+	"github.com/DementevVV/commitsum/internal/domain/entity"
+)
 
 // CacheRepository defines the interface for caching commits.
 type CacheRepository interface {
 	// GetCommits retrieves cached commits for a given author and date range.
 	GetCommits(author, dateRange string) (*entity.CommitData, bool, error)
 
-	// SetCommits stores commits in the cache.
-	SetCommits(author, dateRange string, data *entity.CommitData) error
+	// PeekRepoList returns the cached repository list, ignoring expiration.
+	PeekRepoList(author, dateRange string) []string
+
+	// SetCommits stores commits in the cache. A zero ttl uses the default
+	// expiry for the date range.
+	SetCommits(author, dateRange string, data *entity.CommitData, ttl time.Duration) error
 
 	// Invalidate removes cached data for a user.
 	Invalidate(author string) error
 
 	// Clear removes all cached data.
 	Clear() error
+
+	// Dir returns the directory holding the cache files.
+	Dir() string
 }
@@ -1,13 +1,45 @@
 // Package repository defines the interfaces for external data access.
 package repository
 
-import "github.com/DementevVV/commitsum/internal/domain/entity"
+import (
+	"errors"
+
+	"github.com/DementevVV/commitsum/internal/domain/entity"
+)
+
+// ErrNotAuthenticated matches errors caused by missing or expired GitHub
+// credentials (check with errors.Is).
+var ErrNotAuthenticated = errors.New("GitHub authentication required")
+
+// ErrGHNotInstalled matches errors caused by the gh CLI missing from PATH
+// (check with errors.Is).
+var ErrGHNotInstalled = errors.New("GitHub CLI (gh) is not installed")
 
 // GitHubRepository defines the interface for GitHub data access.
 type GitHubRepository interface {
 	// GetUser returns the currently authenticated GitHub username.
 	GetUser() (string, error)
 
-	// FetchCommitsByAuthorAndDate fetches commits for a given author and date range.
-	FetchCommitsByAuthorAndDate(author, dateRange string) (*entity.CommitData, error)
+	// FetchCommitsByAuthorAndDate fetches commits for a given author and date range,
+	// matching the range against the given date field.
+	FetchCommitsByAuthorAndDate(author, dateRange string, field entity.DateField) (*entity.CommitData, error)
+
+	// FetchCoauthoredCommits fetches commits crediting the author via a Co-authored-by trailer.
+	FetchCoauthoredCommits(author, dateRange string, field entity.DateField) (*entity.CommitData, error)
+
+	// CountCommits returns how many commits an author search matches, without
+	// fetching them.
+	CountCommits(author, dateRange string, field entity.DateField) (int, error)
+
+	// CommitsOnBranch reports, by SHA, which commits are reachable from a
+	// branch. Commits whose check failed are absent from the result.
+	CommitsOnBranch(commits []entity.Commit, branch string) (map[string]bool, error)
+
+	// PullRequestsForCommits returns, by SHA, the pull request each commit
+	// was merged through. Commits without a PR, or whose lookup failed, are
+	// absent from the result.
+	PullRequestsForCommits(commits []entity.Commit) (map[string]entity.PullRequest, error)
+
+	// RateLimitStatus returns the remaining search API quota.
+	RateLimitStatus() (*entity.RateLimit, error)
 }
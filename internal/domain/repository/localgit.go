@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/DementevVV/commitsum/internal/domain/entity"
+
+// LocalGitRepository defines the interface for reading commits from local
+// git checkouts, including ones not yet pushed.
+type LocalGitRepository interface {
+	// FetchCommits returns the local user's commits between two dates
+	// (YYYY-MM-DD, inclusive) across the given repository paths, matching the
+	// range against the given date field.
+	FetchCommits(paths []string, startDate, endDate string, field entity.DateField) (*entity.CommitData, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+// SeenRepository persists which commits the user has already viewed, keyed
+// by date range.
+type SeenRepository interface {
+	// Seen returns the SHAs recorded for a key. found is false when the key
+	// has never been marked.
+	Seen(key string) (shas map[string]bool, found bool, err error)
+
+	// MarkSeen adds SHAs to the set recorded for a key.
+	MarkSeen(key string, shas []string) error
+}
@@ -0,0 +1,64 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		data     string
+		perm     os.FileMode
+	}{
+		{name: "new file", data: `{"a":1}`, perm: 0644},
+		{name: "replaces existing", existing: `{"old":true}`, data: `{"new":true}`, perm: 0644},
+		{name: "applies permissions", data: `{}`, perm: 0600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "entry.json")
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := Write(path, []byte(tt.data), tt.perm); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.data {
+				t.Errorf("content = %q, want %q", got, tt.data)
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != tt.perm {
+				t.Errorf("perm = %v, want %v", info.Mode().Perm(), tt.perm)
+			}
+			if tmps, _ := filepath.Glob(filepath.Join(dir, "*.tmp-*")); len(tmps) != 0 {
+				t.Errorf("temporary files left behind: %v", tmps)
+			}
+		})
+	}
+}
+
+func TestWriteMissingDir(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "entry.json")
+	if err := Write(path, []byte("{}"), 0644); err == nil {
+		t.Fatal("expected an error for a missing directory")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("destination exists after a failed write: %v", err)
+	}
+}
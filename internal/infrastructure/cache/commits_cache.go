@@ -12,9 +12,13 @@ import (
 
 // cachedCommitData represents cached commit data.
 type cachedCommitData struct {
-	Commits  map[string][]entity.Commit `json:"commits"`
-	RepoList []string                   `json:"repo_list"`
-	Warning  string                     `json:"warning"`
+	Commits          map[string][]entity.Commit `json:"commits"`
+	RepoList         []string                   `json:"repo_list"`
+	Warning          string                     `json:"warning"`
+	ExcludedByBranch int                        `json:"excluded_by_branch,omitempty"`
+	DroppedEmpty     int                        `json:"dropped_empty,omitempty"`
+	SearchTotal      int                        `json:"search_total,omitempty"`
+	Capped           bool                       `json:"capped,omitempty"`
 }
 
 // CommitsCache represents a specialized cache for commits.
@@ -34,12 +38,13 @@ func NewCommitsCache() (*CommitsCache, error) {
 	return &CommitsCache{cache: cache}, nil
 }
 
-// GetCommits retrieves cached commits.
+// GetCommits retrieves cached commits. FetchedAt is set to the time the
+// entry was stored.
 func (cc *CommitsCache) GetCommits(author, dateRange string) (*entity.CommitData, bool, error) {
 	key := cc.cache.GetCacheKey("commits", author, dateRange)
 
 	var data cachedCommitData
-	found, err := cc.cache.Get(key, &data)
+	storedAt, found, err := cc.cache.GetWithTimestamp(key, &data)
 	if err != nil {
 		return nil, false, err
 	}
@@ -47,29 +52,53 @@ func (cc *CommitsCache) GetCommits(author, dateRange string) (*entity.CommitData
 	if found {
 		logger.Debug("Commits cache hit", "author", author, "date_range", dateRange)
 		return &entity.CommitData{
-			Commits:  data.Commits,
-			RepoList: data.RepoList,
-			Warning:  data.Warning,
+			Commits:          data.Commits,
+			RepoList:         data.RepoList,
+			Warning:          data.Warning,
+			ExcludedByBranch: data.ExcludedByBranch,
+			DroppedEmpty:     data.DroppedEmpty,
+			SearchTotal:      data.SearchTotal,
+			Capped:           data.Capped,
+			FetchedAt:        storedAt,
 		}, true, nil
 	}
 
 	return nil, false, nil
 }
 
-// SetCommits stores commits in the cache.
-func (cc *CommitsCache) SetCommits(author, dateRange string, commitData *entity.CommitData) error {
+// PeekRepoList returns the repository list of a cached entry, even if it has
+// expired. It returns nil when nothing is cached.
+func (cc *CommitsCache) PeekRepoList(author, dateRange string) []string {
+	key := cc.cache.GetCacheKey("commits", author, dateRange)
+
+	var data cachedCommitData
+	found, err := cc.cache.Peek(key, &data)
+	if err != nil || !found {
+		return nil
+	}
+	return data.RepoList
+}
+
+// SetCommits stores commits in the cache. A zero ttl uses the default expiry.
+func (cc *CommitsCache) SetCommits(author, dateRange string, commitData *entity.CommitData, ttl time.Duration) error {
 	key := cc.cache.GetCacheKey("commits", author, dateRange)
 
 	data := &cachedCommitData{
-		Commits:  commitData.Commits,
-		RepoList: commitData.RepoList,
-		Warning:  commitData.Warning,
+		Commits:          commitData.Commits,
+		RepoList:         commitData.RepoList,
+		Warning:          commitData.Warning,
+		ExcludedByBranch: commitData.ExcludedByBranch,
+		DroppedEmpty:     commitData.DroppedEmpty,
+		SearchTotal:      commitData.SearchTotal,
+		Capped:           commitData.Capped,
 	}
 
 	// Cache for 5 minutes for today, 1 hour for older dates.
-	ttl := 5 * time.Minute
-	if !isToday(dateRange) {
-		ttl = time.Hour
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+		if !isToday(dateRange) {
+			ttl = time.Hour
+		}
 	}
 
 	err := cc.cache.Set(key, data, ttl)
@@ -103,8 +132,15 @@ func (cc *CommitsCache) Clear() error {
 	return cc.cache.Clear()
 }
 
-// isToday checks if the date is today.
+// Dir returns the cache directory, or "" for a nil cache.
+func (cc *CommitsCache) Dir() string {
+	if cc == nil {
+		return ""
+	}
+	return cc.cache.Dir()
+}
+
+// isToday checks if the date is today (honoring the day cutoff hour).
 func isToday(dateRange string) bool {
-	today := time.Now().Format("2006-01-02")
-	return dateRange == today
+	return dateRange == entity.Today() || dateRange == time.Now().Format(entity.DateLayout)
 }
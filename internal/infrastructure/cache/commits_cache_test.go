@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/DementevVV/commitsum/internal/domain/entity"
+	"github.com/DementevVV/commitsum/internal/infrastructure/atomicfile"
+)
+
+func TestSetCommitsTTL(t *testing.T) {
+	tests := []struct {
+		name      string
+		dateRange string
+		ttl       time.Duration
+		want      time.Duration
+	}{
+		{name: "today default", dateRange: entity.Today(), want: 5 * time.Minute},
+		{name: "past default", dateRange: "2024-03-01..2024-03-07", want: time.Hour},
+		{name: "override for today", dateRange: entity.Today(), ttl: 3 * time.Hour, want: 3 * time.Hour},
+		{name: "override for the past", dateRange: "2024-03-01..2024-03-07", ttl: 10 * time.Minute, want: 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := &CommitsCache{cache: &FileCache{dir: t.TempDir()}}
+			if err := cc.SetCommits("octocat", tt.dateRange, &entity.CommitData{}, tt.ttl); err != nil {
+				t.Fatal(err)
+			}
+
+			raw, err := os.ReadFile(cc.cache.getCacheFilePath(cc.cache.GetCacheKey("commits", "octocat", tt.dateRange)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var entry Entry
+			if err := json.Unmarshal(raw, &entry); err != nil {
+				t.Fatal(err)
+			}
+			if entry.TTL != tt.want {
+				t.Errorf("stored ttl = %v, want %v", entry.TTL, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCommitsFetchedAt(t *testing.T) {
+	tests := []struct {
+		name string
+		age  time.Duration
+	}{
+		{name: "fresh", age: 0},
+		{name: "minutes old", age: 3 * time.Minute},
+		{name: "almost expired", age: 59 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := &CommitsCache{cache: &FileCache{dir: t.TempDir()}}
+			key := cc.cache.GetCacheKey("commits", "octocat", "2024-03-01")
+			stored := time.Now().Add(-tt.age).Round(time.Second)
+
+			raw, err := json.Marshal(Entry{
+				Data:      cachedCommitData{RepoList: []string{"acme/api"}},
+				Timestamp: stored,
+				TTL:       time.Hour,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := atomicfile.Write(cc.cache.getCacheFilePath(key), raw, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			data, found, err := cc.GetCommits("octocat", "2024-03-01")
+			if err != nil || !found {
+				t.Fatalf("GetCommits: found=%v err=%v", found, err)
+			}
+			if !data.FetchedAt.Equal(stored) {
+				t.Errorf("FetchedAt = %v, want the entry timestamp %v", data.FetchedAt, stored)
+			}
+		})
+	}
+}
+
+func TestCommitsCacheRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		data entity.CommitData
+	}{
+		{name: "capped", data: entity.CommitData{RepoList: []string{"acme/api"}, SearchTotal: 1000, Capped: true}},
+		{name: "complete", data: entity.CommitData{RepoList: []string{"acme/api"}, SearchTotal: 12, DroppedEmpty: 1, ExcludedByBranch: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := &CommitsCache{cache: &FileCache{dir: t.TempDir()}}
+			if err := cc.SetCommits("octocat", "2024-03-01", &tt.data, time.Hour); err != nil {
+				t.Fatal(err)
+			}
+			got, found, err := cc.GetCommits("octocat", "2024-03-01")
+			if err != nil || !found {
+				t.Fatalf("GetCommits: found=%v err=%v", found, err)
+			}
+			if got.Capped != tt.data.Capped || got.SearchTotal != tt.data.SearchTotal ||
+				got.DroppedEmpty != tt.data.DroppedEmpty || got.ExcludedByBranch != tt.data.ExcludedByBranch {
+				t.Errorf("GetCommits() = %+v, want %+v", got, tt.data)
+			}
+		})
+	}
+}
@@ -7,11 +7,21 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
+	"github.com/DementevVV/commitsum/internal/infrastructure/atomicfile"
 	"github.com/DementevVV/commitsum/internal/infrastructure/logger"
 )
 
+// tempFilePattern matches temporary files of atomicfile.Write left behind by
+// interrupted writes.
+const tempFilePattern = "*.json.tmp-*"
+
+// staleTempAge is how old a temporary file must be before it is treated as
+// orphaned rather than an in-progress write.
+const staleTempAge = time.Minute
+
 // Entry represents a cache entry.
 type Entry struct {
 	Data      interface{}   `json:"data"`
@@ -27,6 +37,9 @@ func (e *Entry) IsExpired() bool {
 // FileCache represents a file-based cache.
 type FileCache struct {
 	dir string
+	// mu serializes writes within this process; the lock file covers
+	// other commitsum instances.
+	mu sync.Mutex
 }
 
 // NewFileCache creates a new file cache.
@@ -41,7 +54,10 @@ func NewFileCache() (*FileCache, error) {
 		return nil, fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
-	return &FileCache{dir: cacheDir}, nil
+	c := &FileCache{dir: cacheDir}
+	c.removeTempFiles(staleTempAge)
+
+	return c, nil
 }
 
 // GetCacheKey generates a cache key based on parameters.
@@ -74,7 +90,10 @@ func (c *FileCache) Set(key string, data interface{}, ttl time.Duration) error {
 	}
 
 	filePath := c.getCacheFilePath(key)
-	if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
+	err = c.withLock(func() error {
+		return atomicfile.Write(filePath, jsonData, 0644)
+	})
+	if err != nil {
 		return fmt.Errorf("failed to write cache file: %w", err)
 	}
 
@@ -84,31 +103,67 @@ func (c *FileCache) Set(key string, data interface{}, ttl time.Duration) error {
 
 // Get retrieves data from the cache.
 func (c *FileCache) Get(key string, target interface{}) (bool, error) {
+	_, found, err := c.GetWithTimestamp(key, target)
+	return found, err
+}
+
+// GetWithTimestamp retrieves data from the cache along with the time the
+// entry was stored.
+func (c *FileCache) GetWithTimestamp(key string, target interface{}) (time.Time, bool, error) {
 	filePath := c.getCacheFilePath(key)
 
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return false, nil // Cache not found.
+			return time.Time{}, false, nil // Cache not found.
 		}
-		return false, fmt.Errorf("failed to read cache file: %w", err)
+		return time.Time{}, false, fmt.Errorf("failed to read cache file: %w", err)
 	}
 
 	var entry Entry
 	if err := json.Unmarshal(data, &entry); err != nil {
 		// Remove corrupted cache file.
 		_ = os.Remove(filePath)
-		return false, fmt.Errorf("failed to unmarshal cache entry: %w", err)
+		return time.Time{}, false, fmt.Errorf("failed to unmarshal cache entry: %w", err)
 	}
 
 	// Check expiration.
 	if entry.IsExpired() {
 		_ = os.Remove(filePath)
 		logger.Debug("Cache entry expired and removed", "key", key)
-		return false, nil
+		return time.Time{}, false, nil
 	}
 
 	// Deserialize data into target structure.
+	entryData, err := json.Marshal(entry.Data)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to marshal entry data: %w", err)
+	}
+
+	if err := json.Unmarshal(entryData, target); err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to unmarshal target data: %w", err)
+	}
+
+	logger.Debug("Cache hit", "key", key)
+	return entry.Timestamp, true, nil
+}
+
+// Peek retrieves data from the cache without checking expiration, for
+// callers that only need a best-effort preview of previous results.
+func (c *FileCache) Peek(key string, target interface{}) (bool, error) {
+	data, err := os.ReadFile(c.getCacheFilePath(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read cache file: %w", err)
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return false, fmt.Errorf("failed to unmarshal cache entry: %w", err)
+	}
+
 	entryData, err := json.Marshal(entry.Data)
 	if err != nil {
 		return false, fmt.Errorf("failed to marshal entry data: %w", err)
@@ -118,7 +173,6 @@ func (c *FileCache) Get(key string, target interface{}) (bool, error) {
 		return false, fmt.Errorf("failed to unmarshal target data: %w", err)
 	}
 
-	logger.Debug("Cache hit", "key", key)
 	return true, nil
 }
 
@@ -142,11 +196,33 @@ func (c *FileCache) Clear() error {
 	for _, file := range files {
 		_ = os.Remove(file)
 	}
+	c.removeTempFiles(0)
 
 	logger.Info("Cache cleared", "files_removed", len(files))
 	return nil
 }
 
+// removeTempFiles removes temporary files older than minAge left behind by
+// interrupted writes, returning how many were removed.
+func (c *FileCache) removeTempFiles(minAge time.Duration) int {
+	files, err := filepath.Glob(filepath.Join(c.dir, tempFilePattern))
+	if err != nil {
+		return 0
+	}
+
+	removed := 0
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil || time.Since(info.ModTime()) < minAge {
+			continue
+		}
+		if err := os.Remove(file); err == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
 // CleanExpired removes expired entries.
 func (c *FileCache) CleanExpired() error {
 	files, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
@@ -175,6 +251,8 @@ func (c *FileCache) CleanExpired() error {
 		}
 	}
 
+	removedCount += c.removeTempFiles(staleTempAge)
+
 	if removedCount > 0 {
 		logger.Info("Expired cache entries cleaned", "removed_count", removedCount)
 	}
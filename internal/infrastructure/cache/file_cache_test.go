@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSetPartialWriteInvisible(t *testing.T) {
+	c := &FileCache{dir: t.TempDir()}
+	key := c.GetCacheKey("commits", "octocat", "2024-03-01")
+
+	if err := c.Set(key, map[string]int{"version": 1}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	// An interrupted write leaves only a truncated temporary file.
+	partial, err := os.CreateTemp(c.dir, key+".tmp-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := partial.WriteString(`{"data":{"vers`); err != nil {
+		t.Fatal(err)
+	}
+	partial.Close()
+
+	var got map[string]int
+	found, err := c.Get(key, &got)
+	if err != nil || !found {
+		t.Fatalf("Get after partial write: found=%v err=%v", found, err)
+	}
+	if got["version"] != 1 {
+		t.Errorf("version = %d, want the previous entry's 1", got["version"])
+	}
+
+	if err := c.Set(key, map[string]int{"version": 2}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if found, err := c.Get(key, &got); err != nil || !found || got["version"] != 2 {
+		t.Errorf("Get after rename = %v (found=%v err=%v), want version 2", got, found, err)
+	}
+}
+
+func TestRemoveTempFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		age     time.Duration
+		minAge  time.Duration
+		removed int
+	}{
+		{name: "fresh write in progress", age: 0, minAge: staleTempAge, removed: 0},
+		{name: "orphaned", age: 2 * staleTempAge, minAge: staleTempAge, removed: 1},
+		{name: "clear removes all", age: 0, minAge: 0, removed: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &FileCache{dir: t.TempDir()}
+			tmp := filepath.Join(c.dir, "entry.json.tmp-123")
+			if err := os.WriteFile(tmp, []byte("{"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			mtime := time.Now().Add(-tt.age)
+			if err := os.Chtimes(tmp, mtime, mtime); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := c.removeTempFiles(tt.minAge); got != tt.removed {
+				t.Errorf("removed %d, want %d", got, tt.removed)
+			}
+		})
+	}
+}
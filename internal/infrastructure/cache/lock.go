@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/DementevVV/commitsum/internal/infrastructure/logger"
+)
+
+const (
+	// lockFileName is the advisory lock shared by all commitsum processes.
+	lockFileName = "commitsum.lock"
+
+	// breakerSuffix names the short-lived file that serializes removing a
+	// stale lock, so two processes never both remove and re-take it.
+	breakerSuffix = ".break"
+
+	// staleLockAge is how old a lock without a readable PID, or a breaker
+	// file, must be before it is treated as left behind by a crash. Writes
+	// hold the lock for milliseconds.
+	staleLockAge = 10 * time.Second
+
+	// maxLockAge is how long a lock whose PID is still alive is honored. Past
+	// it the PID most likely belongs to an unrelated process that reused it.
+	maxLockAge = time.Minute
+
+	// lockRetryInterval is the pause between attempts to take the lock.
+	lockRetryInterval = 25 * time.Millisecond
+)
+
+// lockWait is how long a writer waits for a contended lock.
+var lockWait = 2 * time.Second
+
+// errLockBusy reports that another process held the cache lock for longer
+// than lockWait.
+var errLockBusy = errors.New("cache is locked by another commitsum process")
+
+// withLock runs fn while holding the cache directory's advisory lock. The
+// lock file records the holder's PID. If the lock cannot be taken within
+// lockWait, fn is skipped and an error returned: the write is lost, but a
+// cache miss is refetched later.
+func (c *FileCache) withLock(fn func() error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	lockPath := filepath.Join(c.dir, lockFileName)
+	if err := c.acquireLock(lockPath); err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(lockPath)
+	}()
+	return fn()
+}
+
+// acquireLock creates the lock file, waiting for another holder and removing
+// locks whose holder is gone.
+func (c *FileCache) acquireLock(lockPath string) error {
+	deadline := time.Now().Add(lockWait)
+	contended := false
+
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, _ = fmt.Fprintf(f, "%d\n", os.Getpid())
+			_ = f.Close()
+			return nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("failed to create cache lock: %w", err)
+		}
+
+		if breakStaleLock(lockPath) {
+			continue
+		}
+
+		if !contended {
+			contended = true
+			logger.Info("Cache lock contended, waiting", "holder_pid", readLockPID(lockPath))
+		}
+		if time.Now().After(deadline) {
+			pid := readLockPID(lockPath)
+			logger.Warn("Timed out waiting for cache lock, skipping write", "holder_pid", pid)
+			return fmt.Errorf("%w (pid %d)", errLockBusy, pid)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// breakStaleLock removes the lock if its holder is gone, reporting whether
+// it did. Removal happens while holding a breaker file and after checking
+// staleness again, so a lock just re-taken by a live process survives.
+func breakStaleLock(lockPath string) bool {
+	if _, stale := lockStale(lockPath); !stale {
+		return false
+	}
+
+	breaker := lockPath + breakerSuffix
+	f, err := os.OpenFile(breaker, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		// Another process is breaking the lock. A breaker only outlives
+		// that moment if its owner crashed.
+		if info, statErr := os.Stat(breaker); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			_ = os.Remove(breaker)
+		}
+		return false
+	}
+	_ = f.Close()
+	defer func() {
+		_ = os.Remove(breaker)
+	}()
+
+	pid, stale := lockStale(lockPath)
+	if !stale {
+		return false
+	}
+	logger.Warn("Removing stale cache lock", "holder_pid", pid)
+	return os.Remove(lockPath) == nil
+}
+
+// lockStale reports whether the lock at lockPath was left behind: its holder
+// process is gone, it has no readable PID and is older than staleLockAge,
+// or it is older than maxLockAge. A missing lock is not stale.
+func lockStale(lockPath string) (pid int, stale bool) {
+	info, err := os.Stat(lockPath)
+	if err != nil {
+		return 0, false
+	}
+	age := time.Since(info.ModTime())
+
+	pid = readLockPID(lockPath)
+	if pid <= 0 {
+		// The holder writes its PID right after creating the lock.
+		return pid, age > staleLockAge
+	}
+	return pid, !processAlive(pid) || age > maxLockAge
+}
+
+// processAlive reports whether a process with the given PID exists.
+func processAlive(pid int) bool {
+	if pid == os.Getpid() {
+		return true
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		// Windows fails to open processes that do not exist.
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		_ = p.Release()
+		return true
+	}
+	// Signal 0 checks for existence without delivering anything.
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// readLockPID returns the PID recorded in a lock file, or 0 if unreadable.
+func readLockPID(lockPath string) int {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0
+	}
+	return pid
+}
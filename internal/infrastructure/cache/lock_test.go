@@ -0,0 +1,165 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// deadPID returns the PID of a process that has already exited.
+func deadPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skipf("cannot run a helper process: %v", err)
+	}
+	return cmd.Process.Pid
+}
+
+// shortLockWait shortens lockWait for the duration of a test.
+func shortLockWait(t *testing.T) {
+	t.Helper()
+	prev := lockWait
+	lockWait = 100 * time.Millisecond
+	t.Cleanup(func() { lockWait = prev })
+}
+
+func TestWithLockExistingLock(t *testing.T) {
+	shortLockWait(t)
+
+	tests := []struct {
+		name    string
+		content func(t *testing.T) string
+		age     time.Duration
+		wantRun bool
+	}{
+		{name: "no lock", wantRun: true},
+		{name: "dead holder", content: func(t *testing.T) string { return fmt.Sprint(deadPID(t)) }, wantRun: true},
+		{name: "live holder", content: func(*testing.T) string { return fmt.Sprint(os.Getpid()) }, wantRun: false},
+		{name: "live holder, lock far too old", content: func(*testing.T) string { return fmt.Sprint(os.Getpid()) }, age: 2 * maxLockAge, wantRun: true},
+		{name: "no PID yet", content: func(*testing.T) string { return "" }, wantRun: false},
+		{name: "no PID, old", content: func(*testing.T) string { return "" }, age: 2 * staleLockAge, wantRun: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &FileCache{dir: t.TempDir()}
+			lockPath := filepath.Join(c.dir, lockFileName)
+			if tt.content != nil {
+				if err := os.WriteFile(lockPath, []byte(tt.content(t)), 0644); err != nil {
+					t.Fatal(err)
+				}
+				mtime := time.Now().Add(-tt.age)
+				if err := os.Chtimes(lockPath, mtime, mtime); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ran := false
+			err := c.withLock(func() error {
+				ran = true
+				if pid := readLockPID(lockPath); pid != os.Getpid() {
+					t.Errorf("lock holds pid %d while running, want %d", pid, os.Getpid())
+				}
+				return nil
+			})
+
+			if ran != tt.wantRun {
+				t.Fatalf("fn ran = %v, want %v", ran, tt.wantRun)
+			}
+			if tt.wantRun {
+				if err != nil {
+					t.Errorf("withLock: %v", err)
+				}
+				if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+					t.Errorf("lock not released: %v", err)
+				}
+			} else {
+				if !errors.Is(err, errLockBusy) {
+					t.Errorf("err = %v, want %v", err, errLockBusy)
+				}
+				if _, err := os.Stat(lockPath); err != nil {
+					t.Errorf("held lock was removed: %v", err)
+				}
+			}
+			if _, err := os.Stat(lockPath + breakerSuffix); !os.IsNotExist(err) {
+				t.Errorf("breaker file left behind: %v", err)
+			}
+		})
+	}
+}
+
+func TestWithLockTwoWriters(t *testing.T) {
+	dir := t.TempDir()
+	// Separate FileCache values share only the lock file, like two processes.
+	writers := []*FileCache{{dir: dir}, {dir: dir}}
+
+	const writes = 20
+	var inside, maxInside, done atomic.Int32
+	var wg sync.WaitGroup
+	for _, c := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range writes {
+				err := c.withLock(func() error {
+					n := inside.Add(1)
+					for {
+						m := maxInside.Load()
+						if n <= m || maxInside.CompareAndSwap(m, n) {
+							break
+						}
+					}
+					time.Sleep(time.Millisecond)
+					inside.Add(-1)
+					done.Add(1)
+					return nil
+				})
+				if err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := maxInside.Load(); got != 1 {
+		t.Errorf("%d writers held the lock at once, want 1", got)
+	}
+	if got := done.Load(); got != int32(len(writers)*writes) {
+		t.Errorf("%d writes completed, want %d", got, len(writers)*writes)
+	}
+}
+
+func TestSetSkipsWriteWhenLocked(t *testing.T) {
+	shortLockWait(t)
+
+	c := &FileCache{dir: t.TempDir()}
+	lockPath := filepath.Join(c.dir, lockFileName)
+	if err := os.WriteFile(lockPath, []byte(fmt.Sprint(os.Getpid())), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	key := c.GetCacheKey("commits", "octocat", "2024-03-01")
+	if err := c.Set(key, map[string]int{"v": 1}, time.Hour); !errors.Is(err, errLockBusy) {
+		t.Fatalf("Set err = %v, want %v", err, errLockBusy)
+	}
+	if _, err := os.Stat(c.getCacheFilePath(key)); !os.IsNotExist(err) {
+		t.Errorf("entry written without the lock: %v", err)
+	}
+}
+
+func TestProcessAlive(t *testing.T) {
+	if !processAlive(os.Getpid()) {
+		t.Error("own process reported dead")
+	}
+	if processAlive(deadPID(t)) {
+		t.Error("exited process reported alive")
+	}
+}
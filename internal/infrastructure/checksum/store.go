@@ -0,0 +1,42 @@
+// Package checksum remembers the last exported report checksum per range.
+package checksum
+
+import (
+	"fmt"
+
+	"github.com/DementevVV/commitsum/internal/infrastructure/jsonfile"
+)
+
+// FileStore keeps report checksums in a single JSON file.
+type FileStore struct {
+	path string
+}
+
+// NewFileStore creates a store in checksums.json next to the config file.
+func NewFileStore() (*FileStore, error) {
+	path, err := jsonfile.Path("checksums.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate checksum store: %w", err)
+	}
+	return &FileStore{path: path}, nil
+}
+
+// Get returns the checksum stored for key.
+func (s *FileStore) Get(key string) (string, bool, error) {
+	sums, err := jsonfile.LoadMap[string](s.path)
+	if err != nil {
+		return "", false, err
+	}
+	sum, ok := sums[key]
+	return sum, ok, nil
+}
+
+// Set stores the checksum for key.
+func (s *FileStore) Set(key, sum string) error {
+	sums, err := jsonfile.LoadMap[string](s.path)
+	if err != nil {
+		return err
+	}
+	sums[key] = sum
+	return jsonfile.SaveMap(s.path, sums)
+}
@@ -0,0 +1,32 @@
+package checksum
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DementevVV/commitsum/internal/infrastructure/config"
+)
+
+func TestFileStoreBesideConfig(t *testing.T) {
+	dir := t.TempDir()
+	config.SetPath(filepath.Join(dir, "work.json"))
+	t.Cleanup(func() { config.SetPath("") })
+
+	store, err := NewFileStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := store.Get("2024-03-01..2024-03-07"); ok || err != nil {
+		t.Fatalf("Get() on an empty store = %v, %v, want not found", ok, err)
+	}
+	if err := store.Set("2024-03-01..2024-03-07", "abc"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "checksums.json")); err != nil {
+		t.Fatalf("store is not next to the config file: %v", err)
+	}
+	if sum, ok, err := store.Get("2024-03-01..2024-03-07"); sum != "abc" || !ok || err != nil {
+		t.Errorf("Get() = %q, %v, %v, want abc", sum, ok, err)
+	}
+}
@@ -2,76 +2,125 @@
 package clipboard
 
 import (
+	"errors"
+	"io"
 	"os/exec"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/DementevVV/commitsum/internal/domain/repository"
+	"github.com/DementevVV/commitsum/internal/infrastructure/logger"
 )
 
+// defaultRetryDelay is the pause before retrying a failed copy.
+const defaultRetryDelay = 200 * time.Millisecond
+
+// runner executes clipboard commands. It is swapped out in tests so that
+// command selection and retries can be checked without a real clipboard.
+type runner interface {
+	// LookPath reports whether a command is installed, like exec.LookPath.
+	LookPath(name string) (string, error)
+	// Run runs a command with stdin attached and waits for it to exit.
+	Run(name string, args []string, stdin io.Reader) error
+}
+
+// execRunner runs commands with os/exec.
+type execRunner struct{}
+
+func (execRunner) LookPath(name string) (string, error) {
+	return exec.LookPath(name)
+}
+
+func (execRunner) Run(name string, args []string, stdin io.Reader) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = stdin
+	return cmd.Run()
+}
+
+// command is a clipboard command line. tool is the executable that must be
+// installed, which differs from name on Windows where clip runs via cmd.
+type command struct {
+	tool string
+	name string
+	args []string
+}
+
 // Service provides clipboard operations.
-type Service struct{}
+type Service struct {
+	runner runner
+	goos   string
+	// retries is how many times a copy command that exited non-zero is rerun,
+	// e.g. when wl-copy fails because the compositor is busy.
+	retries    int
+	retryDelay time.Duration
+}
 
 // Ensure Service implements ClipboardRepository.
 var _ repository.ClipboardRepository = (*Service)(nil)
 
-// New creates a new clipboard service.
+// New creates a new clipboard service that retries a failed copy once.
 func New() *Service {
-	return &Service{}
+	return &Service{
+		runner:     execRunner{},
+		goos:       runtime.GOOS,
+		retries:    1,
+		retryDelay: defaultRetryDelay,
+	}
 }
 
-// Copy copies text to the system clipboard.
+// SetRetries sets how many times a failed copy is retried (0 disables).
+func (s *Service) SetRetries(retries int) {
+	s.retries = max(retries, 0)
+}
+
+// Copy copies text to the system clipboard. A copy command that exits
+// non-zero is retried after a short delay; a missing command is not.
 func (s *Service) Copy(text string) error {
-	var cmd *exec.Cmd
+	cmd := s.command()
+	for attempt := 0; ; attempt++ {
+		err := s.runner.Run(cmd.name, cmd.args, strings.NewReader(text))
 
-	switch runtime.GOOS {
-	case "darwin":
-		cmd = exec.Command("pbcopy")
-	case "linux":
-		// Try xclip first, then xsel, then wl-copy (Wayland).
-		if _, err := exec.LookPath("xclip"); err == nil {
-			cmd = exec.Command("xclip", "-selection", "clipboard")
-		} else if _, err := exec.LookPath("xsel"); err == nil {
-			cmd = exec.Command("xsel", "--clipboard", "--input")
-		} else if _, err := exec.LookPath("wl-copy"); err == nil {
-			cmd = exec.Command("wl-copy")
-		} else {
-			// Fallback to xclip anyway, let it fail with proper error.
-			cmd = exec.Command("xclip", "-selection", "clipboard")
+		var exitErr *exec.ExitError
+		if err == nil || !errors.As(err, &exitErr) || attempt >= s.retries {
+			return err
 		}
-	case "windows":
-		cmd = exec.Command("cmd", "/c", "clip")
-	default:
-		cmd = exec.Command("pbcopy") // Default to macOS.
+		logger.Warn("Clipboard copy failed, retrying", "command", cmd.name, "attempt", attempt+1, "error", err)
+		time.Sleep(s.retryDelay)
 	}
-
-	cmd.Stdin = strings.NewReader(text)
-	return cmd.Run()
 }
 
 // IsAvailable checks if clipboard is available on the system.
 func (s *Service) IsAvailable() bool {
-	cmd := s.getCommand()
-	_, err := exec.LookPath(cmd)
+	_, err := s.runner.LookPath(s.command().tool)
 	return err == nil
 }
 
-func (s *Service) getCommand() string {
-	switch runtime.GOOS {
+// command returns the platform's clipboard command.
+func (s *Service) command() command {
+	switch s.goos {
 	case "darwin":
-		return "pbcopy"
+		return command{tool: "pbcopy", name: "pbcopy"}
 	case "linux":
-		if _, err := exec.LookPath("xclip"); err == nil {
-			return "xclip"
-		} else if _, err := exec.LookPath("xsel"); err == nil {
-			return "xsel"
-		} else if _, err := exec.LookPath("wl-copy"); err == nil {
-			return "wl-copy"
+		// Try xclip first, then xsel, then wl-copy (Wayland).
+		if s.installed("xclip") {
+			return command{tool: "xclip", name: "xclip", args: []string{"-selection", "clipboard"}}
+		} else if s.installed("xsel") {
+			return command{tool: "xsel", name: "xsel", args: []string{"--clipboard", "--input"}}
+		} else if s.installed("wl-copy") {
+			return command{tool: "wl-copy", name: "wl-copy"}
 		}
-		return "xclip"
+		// Fallback to xclip anyway, let it fail with proper error.
+		return command{tool: "xclip", name: "xclip", args: []string{"-selection", "clipboard"}}
 	case "windows":
-		return "clip"
+		return command{tool: "clip", name: "cmd", args: []string{"/c", "clip"}}
 	default:
-		return "pbcopy"
+		return command{tool: "pbcopy", name: "pbcopy"} // Default to macOS.
 	}
 }
+
+// installed reports whether a command is on the PATH.
+func (s *Service) installed(name string) bool {
+	_, err := s.runner.LookPath(name)
+	return err == nil
+}
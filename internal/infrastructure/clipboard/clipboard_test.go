@@ -0,0 +1,115 @@
+package clipboard
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"testing"
+)
+
+// fakeRunner serves Run results in order and records each call.
+type fakeRunner struct {
+	installed []string
+	results   []error
+	calls     []string
+	inputs    []string
+}
+
+func (r *fakeRunner) LookPath(name string) (string, error) {
+	if slices.Contains(r.installed, name) {
+		return "/usr/bin/" + name, nil
+	}
+	return "", exec.ErrNotFound
+}
+
+func (r *fakeRunner) Run(name string, args []string, stdin io.Reader) error {
+	in, err := io.ReadAll(stdin)
+	if err != nil {
+		return err
+	}
+	r.calls = append(r.calls, name)
+	r.inputs = append(r.inputs, string(in))
+	if len(r.results) == 0 {
+		return nil
+	}
+	result := r.results[0]
+	r.results = r.results[1:]
+	return result
+}
+
+// exitError is a command that ran and exited non-zero.
+var exitError = &exec.ExitError{ProcessState: &os.ProcessState{}}
+
+func TestCopyRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		retries   int
+		results   []error
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "success", retries: 1, wantCalls: 1},
+		{name: "transient failure", retries: 1, results: []error{exitError, nil}, wantCalls: 2},
+		{name: "persistent failure", retries: 1, results: []error{exitError, exitError, nil}, wantCalls: 2, wantErr: true},
+		{name: "retries disabled", retries: 0, results: []error{exitError, nil}, wantCalls: 1, wantErr: true},
+		{name: "missing command", retries: 1, results: []error{exec.ErrNotFound, nil}, wantCalls: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeRunner{installed: []string{"wl-copy"}, results: tt.results}
+			s := &Service{runner: r, goos: "linux"}
+			s.SetRetries(tt.retries)
+
+			err := s.Copy("summary")
+			if got := err != nil; got != tt.wantErr {
+				t.Errorf("Copy() = %v, want error %v", err, tt.wantErr)
+			}
+			if len(r.calls) != tt.wantCalls {
+				t.Fatalf("ran %d times, want %d", len(r.calls), tt.wantCalls)
+			}
+			for i, in := range r.inputs {
+				if r.calls[i] != "wl-copy" || in != "summary" {
+					t.Errorf("call %d = %s with %q, want wl-copy with the full text", i, r.calls[i], in)
+				}
+			}
+		})
+	}
+}
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		goos      string
+		installed []string
+		wantName  string
+		wantArgs  []string
+		available bool
+	}{
+		{"darwin", []string{"pbcopy"}, "pbcopy", nil, true},
+		{"linux", []string{"xclip", "xsel", "wl-copy"}, "xclip", []string{"-selection", "clipboard"}, true},
+		{"linux", []string{"xsel", "wl-copy"}, "xsel", []string{"--clipboard", "--input"}, true},
+		{"linux", []string{"wl-copy"}, "wl-copy", nil, true},
+		{"linux", nil, "xclip", []string{"-selection", "clipboard"}, false},
+		{"windows", []string{"clip"}, "cmd", []string{"/c", "clip"}, true},
+		{"windows", []string{"cmd"}, "cmd", []string{"/c", "clip"}, false},
+	}
+
+	for _, tt := range tests {
+		s := &Service{runner: &fakeRunner{installed: tt.installed}, goos: tt.goos}
+		cmd := s.command()
+		if cmd.name != tt.wantName || !slices.Equal(cmd.args, tt.wantArgs) {
+			t.Errorf("%s with %v: command = %s %v, want %s %v", tt.goos, tt.installed, cmd.name, cmd.args, tt.wantName, tt.wantArgs)
+		}
+		if got := s.IsAvailable(); got != tt.available {
+			t.Errorf("%s with %v: IsAvailable() = %v, want %v", tt.goos, tt.installed, got, tt.available)
+		}
+	}
+}
+
+func TestNewRetriesOnce(t *testing.T) {
+	s := New()
+	if s.retries != 1 || s.retryDelay != defaultRetryDelay {
+		t.Errorf("New() retries = %d after %s, want 1 after %s", s.retries, s.retryDelay, defaultRetryDelay)
+	}
+}
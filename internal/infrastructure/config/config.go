@@ -5,6 +5,12 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/DementevVV/commitsum/internal/domain/entity"
+	"github.com/DementevVV/commitsum/internal/infrastructure/logger"
 )
 
 // Config represents the application configuration.
@@ -16,27 +22,198 @@ type Config struct {
 	// OutputFormat is the output format: "text", "markdown", "json".
 	OutputFormat string `json:"output_format"`
 	// CustomTemplate is a custom template for output.
+	//
+	// Deprecated: use Templates; it is applied as the "text" template.
 	CustomTemplate string `json:"custom_template"`
+	// Templates maps export format names ("text", "markdown", "json",
+	// "pr-body", "github-tasks", "daily") to Go templates replacing the built-in renderer.
+	Templates map[string]string `json:"templates"`
 	// AutoCopy enables automatic copying to clipboard.
 	AutoCopy bool `json:"auto_copy"`
 	// ShowStats enables statistics display.
 	ShowStats bool `json:"show_stats"`
+	// SessionSummary prints a local one-line activity summary to stderr on exit.
+	SessionSummary bool `json:"session_summary"`
+	// APIConcurrency caps parallel gh api calls for per-commit checks such as branch filtering (1-16).
+	APIConcurrency int `json:"api_concurrency"`
+	// ExportLineEnding is the line ending for exported files: "lf" or "crlf" (clipboard copies stay LF).
+	ExportLineEnding string `json:"export_line_ending"`
+	// ExportBOM prepends a UTF-8 byte order mark to exported files.
+	ExportBOM bool `json:"export_bom"`
+	// ClipboardRetries is how many times a failed clipboard command is retried (0-3).
+	ClipboardRetries int `json:"clipboard_retries"`
+	// HideHelpBar starts with the key hints hidden (toggled with h).
+	HideHelpBar bool `json:"hide_help_bar"`
+	// IncludeCoauthored also fetches commits crediting the user via Co-authored-by.
+	IncludeCoauthored bool `json:"include_coauthored"`
+	// DisplayDateFormat is the Go time layout used to show dates in the UI and exports.
+	DisplayDateFormat string `json:"display_date_format"`
+	// FilenameDateFormat is the Go time layout used in export filenames.
+	FilenameDateFormat string `json:"filename_date_format"`
+	// DateField is the commit date ranges match against: "committer-date" or "author-date".
+	DateField string `json:"date_field"`
+	// DropEmptyMessages drops commits with no message instead of showing "(no message)".
+	DropEmptyMessages bool `json:"drop_empty_messages"`
+	// Teammate is the default GitHub username for the comparison view.
+	Teammate string `json:"teammate"`
+	// CollapsibleMarkdown wraps each repository in a <details> block in markdown exports.
+	CollapsibleMarkdown bool `json:"collapsible_markdown"`
+	// GroupByOwnership splits summaries into owned and contributed repositories.
+	GroupByOwnership bool `json:"group_by_ownership"`
+	// GroupByPR groups each repository's commits under the pull request that merged them (one API call per commit).
+	GroupByPR bool `json:"group_by_pr"`
+	// OwnedOrgs lists organizations whose repositories count as owned.
+	OwnedOrgs []string `json:"owned_orgs"`
+	// CommitOrder orders commits within a repository: "api", "message", "time-asc", "time-desc".
+	CommitOrder string `json:"commit_order"`
+	// Branch restricts results to commits reachable from this branch (one API call per commit).
+	Branch string `json:"branch"`
+	// RepoSort is the initial repository list order: "name", "count", "owner".
+	RepoSort string `json:"repo_sort"`
+	// DeterministicExport sorts exported commits and omits the generation time (JSON field and footer) so reruns produce identical files.
+	DeterministicExport bool `json:"deterministic_export"`
+	// ExportRepoOrder orders repository sections in exports: "name" or "count".
+	ExportRepoOrder string `json:"export_repo_order"`
+	// DayCutoffHour is the local hour (0-23) at which a new day starts for date presets.
+	DayCutoffHour int `json:"day_cutoff_hour"`
+	// ExcludeToday ends the week and month presets yesterday (toggled with T).
+	ExcludeToday bool `json:"exclude_today"`
+	// MessageCase normalizes commit headline casing: "none", "sentence", "lower", "title".
+	MessageCase string `json:"message_case"`
+	// MaxExpandedRepos caps how many selected repos show their commits in the list (0 = no limit).
+	MaxExpandedRepos int `json:"max_expanded_repos"`
+	// PRBodyTemplate is a Go template for PR body exports (empty = built-in layout).
+	PRBodyTemplate string `json:"pr_body_template"`
+	// PRBodyFile is where PR body exports are saved (empty = .git/PULLREQUEST_MSG or PULLREQUEST_MSG.md).
+	PRBodyFile string `json:"pr_body_file"`
+	// PostProcessCommand is a shell command export content is piped through before copy/save.
+	PostProcessCommand string `json:"post_process_command"`
+	// ExportFooter adds a "Generated by commitsum" line to text and markdown exports.
+	ExportFooter bool `json:"export_footer"`
+	// ValidateTodayCache re-checks cached ranges that include today against a commit count.
+	ValidateTodayCache bool `json:"validate_today_cache"`
+	// IncludeLocalCommits merges commits from the LocalRepos checkouts, including unpushed ones.
+	IncludeLocalCommits bool `json:"include_local_commits"`
+	// LocalRepos are paths to local git checkouts read when IncludeLocalCommits is set.
+	LocalRepos []string `json:"local_repos"`
+	// ConfirmDestructive asks for confirmation before irreversible actions such as clearing the cache.
+	ConfirmDestructive bool `json:"confirm_destructive"`
+	// StatsStyle renders per-repo statistics as "bar" (gradient bars) or "text" (plain percentages for screen readers).
+	StatsStyle string `json:"stats_style"`
+	// StatsScale sizes statistics bars "linear" or "log" (keeps small repos visible next to a dominant one).
+	StatsScale string `json:"stats_scale"`
+	// CustomPresets adds "last N days" entries to the date range presets.
+	CustomPresets []CustomPreset `json:"custom_presets"`
+	// Weekdays limits results to commits made on these days, e.g. ["saturday", "sunday"] (empty = all days).
+	Weekdays []string `json:"weekdays"`
+	// EarliestDate is the earliest allowed start date, YYYY-MM-DD (empty = no floor).
+	EarliestDate string `json:"earliest_date"`
+	// LargeRangeDays asks for confirmation before loading ranges longer than this many days (0 = never ask).
+	LargeRangeDays int `json:"large_range_days"`
+	// SummaryMinCommits omits repos with fewer commits from the summary and exports (0 = keep all).
+	SummaryMinCommits int `json:"summary_min_commits"`
+	// RepoAliases maps owner/name to a friendly label shown in views and exports.
+	RepoAliases map[string]string `json:"repo_aliases"`
+	// ExcludeReverts leaves revert commits out of statistics counts (they are still shown as reverts).
+	ExcludeReverts bool `json:"exclude_reverts"`
+	// ShowSHA prefixes commits in the summary and exports with their short SHA.
+	ShowSHA bool `json:"show_sha"`
+	// WatchlistRepos are listed under "No activity" in summaries and exports when they have no commits.
+	WatchlistRepos []string `json:"watchlist_repos"`
+	// FavoriteRepos are pinned to the top of the repository list.
+	FavoriteRepos []string `json:"favorite_repos"`
+	// BulletStyle is the commit line marker in views and text/markdown exports:
+	// "•", "-", "*" or "→" (empty = "•" in views, "-" in exports).
+	BulletStyle string `json:"bullet_style"`
+	// CollapsedOwners are owners whose repositories never show commits inline in the list.
+	CollapsedOwners []string `json:"collapsed_owners"`
+	// SelectionProfiles maps a profile name to the repositories it selects.
+	SelectionProfiles map[string][]string `json:"selection_profiles"`
+}
+
+// CustomPreset is a user-defined "last N days" date range preset.
+type CustomPreset struct {
+	// Label is shown in the preset list (empty = "Last N days").
+	Label string `json:"label"`
+	// Days is how many days back the range starts; must be positive.
+	Days int `json:"days"`
+}
+
+// ExportOptions returns the export presentation options from the config.
+func (c Config) ExportOptions() entity.ExportOptions {
+	return entity.ExportOptions{
+		IncludeStats:        c.ShowStats,
+		Footer:              c.ExportFooter,
+		CollapsibleMarkdown: c.CollapsibleMarkdown,
+		MessageCase:         entity.MessageCase(c.MessageCase),
+		ShowSHA:             c.ShowSHA,
+		RepoAliases:         c.RepoAliases,
+		Bullet:              c.BulletStyle,
+		Watchlist:           c.WatchlistRepos,
+		GroupByPR:           c.GroupByPR,
+		Deterministic:       c.DeterministicExport,
+	}
 }
 
 // Default returns a config with default values.
 func Default() Config {
 	return Config{
-		DefaultDateRange: "today",
-		RepoFilter:       "",
-		OutputFormat:     "text",
-		CustomTemplate:   "",
-		AutoCopy:         false,
-		ShowStats:        true,
+		DefaultDateRange:    "today",
+		RepoFilter:          "",
+		OutputFormat:        "text",
+		CustomTemplate:      "",
+		AutoCopy:            false,
+		ShowStats:           true,
+		IncludeCoauthored:   false,
+		DisplayDateFormat:   "2006-01-02",
+		FilenameDateFormat:  "2006-01-02",
+		DateField:           "committer-date",
+		DropEmptyMessages:   false,
+		Teammate:            "",
+		CollapsibleMarkdown: false,
+		GroupByOwnership:    false,
+		OwnedOrgs:           nil,
+		CommitOrder:         "api",
+		Branch:              "",
+		RepoSort:            "name",
+		ExportRepoOrder:     "name",
+		DayCutoffHour:       0,
+		ExcludeToday:        false,
+		MessageCase:         "none",
+		MaxExpandedRepos:    5,
+		PRBodyTemplate:      "",
+		PRBodyFile:          "",
+		PostProcessCommand:  "",
+		ExportFooter:        true,
+		ValidateTodayCache:  false,
+		IncludeLocalCommits: false,
+		LocalRepos:          nil,
+		ConfirmDestructive:  true,
+		StatsStyle:          "bar",
+		StatsScale:          "linear",
+		APIConcurrency:      4,
+		ClipboardRetries:    1,
+		ExportLineEnding:    "lf",
+		EarliestDate:        "",
+		LargeRangeDays:      90,
+		SummaryMinCommits:   0,
 	}
 }
 
+// pathOverride replaces the default config location when set (--config).
+var pathOverride string
+
+// SetPath makes Load, Save and Exists use path instead of the default
+// location. An empty path restores the default.
+func SetPath(path string) {
+	pathOverride = path
+}
+
 // Path returns the path to the config file.
 func Path() (string, error) {
+	if pathOverride != "" {
+		return pathOverride, nil
+	}
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
@@ -53,17 +230,183 @@ func Load() Config {
 
 	data, err := os.ReadFile(configPath)
 	if err != nil {
+		// A missing default config is normal on first run; an explicit one is not.
+		if pathOverride != "" {
+			logger.Warn("Config file not readable, using defaults", "path", configPath, "error", err)
+		}
 		return Default()
 	}
 
-	var cfg Config
+	cfg := Default()
 	if err := json.Unmarshal(data, &cfg); err != nil {
+		logger.Warn("Invalid config file, using defaults", "path", configPath, "error", err)
 		return Default()
 	}
 
+	cfg.validate()
 	return cfg
 }
 
+// validate replaces invalid values with their defaults.
+func (c *Config) validate() {
+	defaults := Default()
+
+	if c.CustomTemplate != "" {
+		if c.Templates == nil {
+			c.Templates = make(map[string]string)
+		}
+		if _, ok := c.Templates["text"]; !ok {
+			c.Templates["text"] = c.CustomTemplate
+		}
+	}
+	for format, tmpl := range c.Templates {
+		switch format {
+		case "text", "markdown", "json", "pr-body", "github-tasks", "daily":
+		default:
+			logger.Warn("Templates are not supported for this format, ignoring", "format", format)
+			delete(c.Templates, format)
+			continue
+		}
+		if _, err := template.New(format).Parse(tmpl); err != nil {
+			logger.Warn("Invalid template, using built-in format", "format", format, "error", err.Error())
+			delete(c.Templates, format)
+		}
+	}
+
+	if !isValidDateLayout(c.DisplayDateFormat) {
+		logger.Warn("Invalid display_date_format, using default", "value", c.DisplayDateFormat)
+		c.DisplayDateFormat = defaults.DisplayDateFormat
+	}
+	if !isValidDateLayout(c.FilenameDateFormat) || strings.ContainsAny(c.FilenameDateFormat, `/\:`) {
+		logger.Warn("Invalid filename_date_format, using default", "value", c.FilenameDateFormat)
+		c.FilenameDateFormat = defaults.FilenameDateFormat
+	}
+	if c.DateField != "committer-date" && c.DateField != "author-date" {
+		logger.Warn("Invalid date_field, using default", "value", c.DateField)
+		c.DateField = defaults.DateField
+	}
+	switch c.CommitOrder {
+	case "api", "message", "time-asc", "time-desc":
+	default:
+		logger.Warn("Invalid commit_order, using default", "value", c.CommitOrder)
+		c.CommitOrder = defaults.CommitOrder
+	}
+	switch c.ExportRepoOrder {
+	case "name", "count":
+	default:
+		logger.Warn("Invalid export_repo_order, using default", "value", c.ExportRepoOrder)
+		c.ExportRepoOrder = defaults.ExportRepoOrder
+	}
+	switch c.RepoSort {
+	case "name", "count", "owner":
+	default:
+		logger.Warn("Invalid repo_sort, using default", "value", c.RepoSort)
+		c.RepoSort = defaults.RepoSort
+	}
+	if c.DayCutoffHour < 0 || c.DayCutoffHour > 23 {
+		logger.Warn("Invalid day_cutoff_hour, using default", "value", c.DayCutoffHour)
+		c.DayCutoffHour = defaults.DayCutoffHour
+	}
+	if c.MaxExpandedRepos < 0 {
+		logger.Warn("Invalid max_expanded_repos, using default", "value", c.MaxExpandedRepos)
+		c.MaxExpandedRepos = defaults.MaxExpandedRepos
+	}
+	switch c.MessageCase {
+	case "none", "sentence", "lower", "title":
+	default:
+		logger.Warn("Invalid message_case, using default", "value", c.MessageCase)
+		c.MessageCase = defaults.MessageCase
+	}
+	switch c.StatsStyle {
+	case "bar", "text":
+	default:
+		logger.Warn("Invalid stats_style, using default", "value", c.StatsStyle)
+		c.StatsStyle = defaults.StatsStyle
+	}
+	switch c.BulletStyle {
+	case "", "•", "-", "*", "→":
+	default:
+		logger.Warn("Invalid bullet_style, using default", "value", c.BulletStyle)
+		c.BulletStyle = defaults.BulletStyle
+	}
+	if c.APIConcurrency < 1 || c.APIConcurrency > 16 {
+		logger.Warn("Invalid api_concurrency, using default", "value", c.APIConcurrency)
+		c.APIConcurrency = defaults.APIConcurrency
+	}
+	if c.ClipboardRetries < 0 || c.ClipboardRetries > 3 {
+		logger.Warn("Invalid clipboard_retries, using default", "value", c.ClipboardRetries)
+		c.ClipboardRetries = defaults.ClipboardRetries
+	}
+	switch c.ExportLineEnding {
+	case "lf", "crlf":
+	default:
+		logger.Warn("Invalid export_line_ending, using default", "value", c.ExportLineEnding)
+		c.ExportLineEnding = defaults.ExportLineEnding
+	}
+	switch c.StatsScale {
+	case "linear", "log":
+	default:
+		logger.Warn("Invalid stats_scale, using default", "value", c.StatsScale)
+		c.StatsScale = defaults.StatsScale
+	}
+	presets := c.CustomPresets[:0]
+	for _, preset := range c.CustomPresets {
+		if preset.Days <= 0 {
+			logger.Warn("Invalid custom_presets entry, ignoring", "label", preset.Label, "days", preset.Days)
+			continue
+		}
+		presets = append(presets, preset)
+	}
+	c.CustomPresets = presets
+	weekdays := c.Weekdays[:0]
+	for _, day := range c.Weekdays {
+		if _, ok := entity.ParseWeekday(day); !ok {
+			logger.Warn("Invalid weekdays entry, ignoring", "value", day)
+			continue
+		}
+		weekdays = append(weekdays, day)
+	}
+	c.Weekdays = weekdays
+	if c.EarliestDate != "" {
+		if _, err := time.Parse(entity.DateLayout, c.EarliestDate); err != nil {
+			logger.Warn("Invalid earliest_date, ignoring", "value", c.EarliestDate)
+			c.EarliestDate = defaults.EarliestDate
+		}
+	}
+	if c.LargeRangeDays < 0 {
+		logger.Warn("Invalid large_range_days, using default", "value", c.LargeRangeDays)
+		c.LargeRangeDays = defaults.LargeRangeDays
+	}
+	if c.SummaryMinCommits < 0 {
+		logger.Warn("Invalid summary_min_commits, using default", "value", c.SummaryMinCommits)
+		c.SummaryMinCommits = defaults.SummaryMinCommits
+	}
+}
+
+// isValidDateLayout checks that a Go time layout round-trips a full date.
+func isValidDateLayout(layout string) bool {
+	if layout == "" {
+		return false
+	}
+	ref := time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC)
+	parsed, err := time.Parse(layout, ref.Format(layout))
+	if err != nil {
+		return false
+	}
+	return parsed.Year() == ref.Year() && parsed.Month() == ref.Month() && parsed.Day() == ref.Day()
+}
+
+// Exists reports whether a config file has been written, which is false on
+// first run.
+func Exists() bool {
+	configPath, err := Path()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(configPath)
+	return err == nil
+}
+
 // Save saves configuration to file.
 func Save(cfg Config) error {
 	configPath, err := Path()
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DementevVV/commitsum/internal/infrastructure/logger"
+)
+
+// usePath points the config at path for the test.
+func usePath(t *testing.T, path string) {
+	t.Helper()
+	SetPath(path)
+	t.Cleanup(func() { SetPath("") })
+}
+
+func TestLoadExplicitPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "work.json")
+	usePath(t, path)
+	data := `{"output_format": "markdown", "summary_min_commits": 2, "owned_orgs": ["acme"]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := Path(); err != nil || got != path {
+		t.Fatalf("Path() = %q, %v, want %q", got, err, path)
+	}
+	if !Exists() {
+		t.Error("Exists() = false for the explicit path")
+	}
+
+	cfg := Load()
+	want := Default()
+	want.OutputFormat = "markdown"
+	want.SummaryMinCommits = 2
+	want.OwnedOrgs = []string{"acme"}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Load() = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestSaveExplicitPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "personal.json")
+	usePath(t, path)
+
+	cfg := Default()
+	cfg.OutputFormat = "json"
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("Save did not write the explicit path: %v", err)
+	}
+	if got := Load(); !reflect.DeepEqual(got, cfg) {
+		t.Errorf("Load() after Save = %+v, want %+v", got, cfg)
+	}
+}
+
+func TestLoadExplicitPathFallsBack(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents *string
+		wantLog  string
+	}{
+		{name: "missing", wantLog: "Config file not readable, using defaults"},
+		{name: "invalid", contents: ptr("{not json"), wantLog: "Invalid config file, using defaults"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "work.json")
+			usePath(t, path)
+			if tt.contents != nil {
+				if err := os.WriteFile(path, []byte(*tt.contents), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var log strings.Builder
+			t.Cleanup(logger.SetOutput(&log, logger.LevelWarn))
+
+			if got := Load(); !reflect.DeepEqual(got, Default()) {
+				t.Errorf("Load() = %+v, want defaults", got)
+			}
+			if !strings.Contains(log.String(), tt.wantLog) || !strings.Contains(log.String(), path) {
+				t.Errorf("log = %q, want %q naming %s", log.String(), tt.wantLog, path)
+			}
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
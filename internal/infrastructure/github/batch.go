@@ -0,0 +1,46 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// defaultConcurrency is how many gh api calls enrichment runs at once.
+const defaultConcurrency = 4
+
+// runBatch calls fn for each index in [0, n) with at most c.concurrency calls
+// in flight, so per-item API enrichment stays rate-limit friendly. Every item
+// is processed; errors are joined. Items not yet started when ctx is done
+// report ctx.Err().
+func (c *Client) runBatch(ctx context.Context, n int, fn func(ctx context.Context, i int) error) error {
+	workers := c.concurrency
+	if workers <= 0 {
+		workers = defaultConcurrency
+	}
+	workers = min(workers, n)
+
+	jobs := make(chan int)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := ctx.Err(); err != nil {
+					errs[i] = err
+					continue
+				}
+				errs[i] = fn(ctx, i)
+			}
+		}()
+	}
+	for i := range n {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
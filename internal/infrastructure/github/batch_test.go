@@ -0,0 +1,122 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunBatchConcurrencyCap(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		wantMax     int
+	}{
+		{"configured", 3, 3},
+		{"default", 0, defaultConcurrency},
+		{"one", 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient()
+			c.SetConcurrency(tt.concurrency)
+
+			const n = 20
+			var inFlight, peak atomic.Int32
+			var mu sync.Mutex
+			seen := make(map[int]int)
+			err := c.runBatch(context.Background(), n, func(_ context.Context, i int) error {
+				cur := inFlight.Add(1)
+				defer inFlight.Add(-1)
+				for {
+					p := peak.Load()
+					if cur <= p || peak.CompareAndSwap(p, cur) {
+						break
+					}
+				}
+				time.Sleep(2 * time.Millisecond)
+				mu.Lock()
+				seen[i]++
+				mu.Unlock()
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("runBatch: %v", err)
+			}
+
+			if got := int(peak.Load()); got > tt.wantMax {
+				t.Errorf("peak concurrency = %d, want at most %d", got, tt.wantMax)
+			}
+			if len(seen) != n {
+				t.Errorf("processed %d items, want %d", len(seen), n)
+			}
+			for i, count := range seen {
+				if count != 1 {
+					t.Errorf("item %d processed %d times, want once", i, count)
+				}
+			}
+		})
+	}
+}
+
+func TestRunBatchAggregatesErrors(t *testing.T) {
+	c := NewClient()
+	c.SetConcurrency(2)
+
+	var processed atomic.Int32
+	errOdd := errors.New("odd item")
+	err := c.runBatch(context.Background(), 6, func(_ context.Context, i int) error {
+		processed.Add(1)
+		if i%2 == 1 {
+			return fmt.Errorf("item %d: %w", i, errOdd)
+		}
+		return nil
+	})
+
+	if got := processed.Load(); got != 6 {
+		t.Errorf("processed %d items, want all 6 despite errors", got)
+	}
+	if !errors.Is(err, errOdd) {
+		t.Fatalf("err = %v, want it to wrap %v", err, errOdd)
+	}
+	for _, i := range []int{1, 3, 5} {
+		want := fmt.Sprintf("item %d", i)
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %q, want it to mention %q", err, want)
+		}
+	}
+}
+
+func TestRunBatchCancelled(t *testing.T) {
+	c := NewClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var called atomic.Int32
+	err := c.runBatch(ctx, 3, func(_ context.Context, _ int) error {
+		called.Add(1)
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if got := called.Load(); got != 0 {
+		t.Errorf("fn called %d times after cancellation, want 0", got)
+	}
+}
+
+func TestRunBatchEmpty(t *testing.T) {
+	err := NewClient().runBatch(context.Background(), 0, func(context.Context, int) error {
+		t.Error("fn called for an empty batch")
+		return nil
+	})
+	if err != nil {
+		t.Errorf("runBatch(0) = %v, want nil", err)
+	}
+}
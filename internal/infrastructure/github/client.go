@@ -10,7 +10,9 @@ import (
 	"io"
 	"os/exec"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/DementevVV/commitsum/internal/domain/entity"
@@ -24,16 +26,43 @@ type commitSearchItem struct {
 		NameWithOwner string `json:"nameWithOwner"`
 		Name          string `json:"name"`
 	} `json:"repository"`
+	Sha    string `json:"sha"`
 	Commit struct {
 		Message         string `json:"message"`
 		MessageHeadline string `json:"messageHeadline"`
+		Author          struct {
+			Date string `json:"date"`
+		} `json:"author"`
+		Committer struct {
+			Date string `json:"date"`
+		} `json:"committer"`
 	} `json:"commit"`
 }
 
+// date returns the commit timestamp for the given date field.
+func (item commitSearchItem) date(field entity.DateField) time.Time {
+	raw := item.Commit.Committer.Date
+	if field == entity.DateFieldAuthor {
+		raw = item.Commit.Author.Date
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// emptyMessagePlaceholder replaces commit messages that are entirely empty.
+const emptyMessagePlaceholder = "(no message)"
+
 // Client encapsulates GitHub API operations via the gh CLI.
 type Client struct {
-	timeout time.Duration
-	limit   int
+	ctx               context.Context
+	timeout           time.Duration
+	limit             int
+	dropEmptyMessages bool
+	// concurrency caps parallel gh api calls in runBatch.
+	concurrency int
 }
 
 // Ensure Client implements GitHubRepository.
@@ -42,14 +71,34 @@ var _ repository.GitHubRepository = (*Client)(nil)
 // NewClient creates a new GitHub client with default settings.
 func NewClient() *Client {
 	return &Client{
-		timeout: 20 * time.Second,
-		limit:   1000,
+		ctx:         context.Background(),
+		timeout:     20 * time.Second,
+		limit:       1000,
+		concurrency: defaultConcurrency,
 	}
 }
 
+// SetContext sets the parent context for gh commands, allowing in-flight
+// requests to be cancelled (e.g. on SIGINT).
+func (c *Client) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// SetDropEmptyMessages controls whether commits without any message are
+// dropped instead of kept with a placeholder.
+func (c *Client) SetDropEmptyMessages(drop bool) {
+	c.dropEmptyMessages = drop
+}
+
+// SetConcurrency sets how many gh api calls enrichment runs in parallel.
+// Values below 1 use the default.
+func (c *Client) SetConcurrency(n int) {
+	c.concurrency = n
+}
+
 // GetUser retrieves the currently authenticated GitHub username using the GitHub CLI.
 func (c *Client) GetUser() (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, "gh", "api", "user", "--jq", ".login")
@@ -58,47 +107,302 @@ func (c *Client) GetUser() (string, error) {
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			return "", fmt.Errorf("gh api user timed out after %s", c.timeout)
 		}
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return "", fmt.Errorf("gh api user cancelled")
+		}
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", fmt.Errorf("%w: install it from https://cli.github.com/", repository.ErrGHNotInstalled)
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			wrapped := WrapError(cmd, exitErr.Stderr, err)
+			// The 401 text varies between gh versions; ask gh directly.
+			if !errors.Is(wrapped, repository.ErrNotAuthenticated) && !c.isAuthenticated() {
+				return "", fmt.Errorf("%w: gh is installed but not logged in, run 'gh auth login'", repository.ErrNotAuthenticated)
+			}
+			return "", wrapped
+		}
 		return "", err
 	}
 
 	return strings.TrimSpace(string(out)), nil
 }
 
+// isAuthenticated reports whether gh auth status succeeds. Failures to run
+// the check count as authenticated so other errors are reported as is.
+func (c *Client) isAuthenticated() bool {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "gh", "auth", "status")
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	return !errors.As(err, &exitErr)
+}
+
 // FetchCommitsByAuthorAndDate fetches commits for a given author and date range.
-func (c *Client) FetchCommitsByAuthorAndDate(author, dateRange string) (*entity.CommitData, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+func (c *Client) FetchCommitsByAuthorAndDate(author, dateRange string, field entity.DateField) (*entity.CommitData, error) {
+	items, err := c.searchCommits("--author", author, dateFlag(field), dateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	data := c.buildCommitData(items, field, false)
+	data.SearchTotal = len(items)
+	return data, nil
+}
+
+// CountCommits returns the total number of commits an author search matches,
+// requesting a single result so it costs one cheap search API call.
+func (c *Client) CountCommits(author, dateRange string, field entity.DateField) (int, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+
+	query := fmt.Sprintf("author:%s %s:%s", author, strings.TrimPrefix(dateFlag(field), "--"), dateRange)
+	cmd := exec.CommandContext(ctx, "gh", "api", "-X", "GET", "search/commits",
+		"-f", "q="+query, "-f", "per_page=1", "--jq", ".total_count")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return 0, fmt.Errorf("gh api search/commits timed out after %s", c.timeout)
+		}
+		return 0, WrapError(cmd, out, err)
+	}
+
+	total, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse commit count: %w", err)
+	}
+	return total, nil
+}
+
+// maxCoauthorSearches bounds how many of the user's addresses are searched
+// for co-authored commits, as each costs one search API call.
+const maxCoauthorSearches = 3
+
+// FetchCoauthoredCommits fetches commits that credit the author through a
+// Co-authored-by trailer. Commit search has no co-author qualifier, so this
+// runs a text search per address of the user and keeps only commits whose
+// trailer carries one of those addresses.
+func (c *Client) FetchCoauthoredCommits(author, dateRange string, field entity.DateField) (*entity.CommitData, error) {
+	emails := c.coauthorEmails(author)
+	known := make(map[string]bool, len(emails))
+	for _, email := range emails {
+		known[strings.ToLower(email)] = true
+	}
+
+	var matched []commitSearchItem
+	seen := make(map[string]bool)
+	for _, email := range emails[:min(len(emails), maxCoauthorSearches)] {
+		items, err := c.searchCommits(email, "co-authored-by", dateFlag(field), dateRange)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if !seen[item.Sha] && hasCoauthorTrailer(item.Commit.Message, author, known) {
+				seen[item.Sha] = true
+				matched = append(matched, item)
+			}
+		}
+	}
+
+	return c.buildCommitData(matched, field, true), nil
+}
+
+// coauthorEmails returns the addresses that identify the user in
+// Co-authored-by trailers: the GitHub noreply address first, then the
+// account's verified emails when gh is allowed to read them (user:email
+// scope). Lookup failures only narrow the list.
+func (c *Client) coauthorEmails(login string) []string {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+
+	var emails []string
+	if out, err := exec.CommandContext(ctx, "gh", "api", "user", "--jq", ".id").Output(); err == nil {
+		if id := strings.TrimSpace(string(out)); id != "" {
+			emails = append(emails, fmt.Sprintf("%s+%s@users.noreply.github.com", id, login))
+		}
+	}
+	out, err := exec.CommandContext(ctx, "gh", "api", "user/emails", "--jq", ".[] | select(.verified) | .email").Output()
+	if err == nil {
+		for _, email := range strings.Fields(string(out)) {
+			if !strings.HasSuffix(email, "@users.noreply.github.com") {
+				emails = append(emails, email)
+			}
+		}
+	}
+	// Older accounts commit as login@users.noreply.github.com.
+	return append(emails, login+"@users.noreply.github.com")
+}
+
+// IsCommitOnBranch reports whether a commit is reachable from a branch by
+// comparing the branch head with the commit: "identical" or "behind" means
+// the commit is part of the branch history.
+func (c *Client) IsCommitOnBranch(repo, sha, branch string) (bool, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+
+	endpoint := fmt.Sprintf("repos/%s/compare/%s...%s", repo, branch, sha)
+	cmd := exec.CommandContext(ctx, "gh", "api", endpoint, "--jq", ".status")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return false, fmt.Errorf("gh api compare timed out after %s", c.timeout)
+		}
+		return false, WrapError(cmd, out, err)
+	}
+
+	status := strings.TrimSpace(string(out))
+	return status == "identical" || status == "behind", nil
+}
+
+// CommitsOnBranch checks which commits are reachable from a branch, running
+// the per-commit compare calls through runBatch. The result maps SHA to
+// reachability; commits whose check failed are absent and the failures are
+// returned joined.
+func (c *Client) CommitsOnBranch(commits []entity.Commit, branch string) (map[string]bool, error) {
+	var mu sync.Mutex
+	result := make(map[string]bool, len(commits))
+	err := c.runBatch(c.ctx, len(commits), func(_ context.Context, i int) error {
+		commit := commits[i]
+		onBranch, err := c.IsCommitOnBranch(commit.Repository, commit.SHA, branch)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		result[commit.SHA] = onBranch
+		mu.Unlock()
+		return nil
+	})
+	return result, err
+}
+
+// PullRequestForCommit returns the pull request a commit was merged through.
+// ok is false when the commit is not associated with any PR.
+func (c *Client) PullRequestForCommit(repo, sha string) (pr entity.PullRequest, ok bool, err error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+
+	endpoint := fmt.Sprintf("repos/%s/commits/%s/pulls", repo, sha)
+	cmd := exec.CommandContext(ctx, "gh", "api", endpoint, "--jq", ".[0] | select(. != null) | {number, title}")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return pr, false, fmt.Errorf("gh api commit pulls timed out after %s", c.timeout)
+		}
+		return pr, false, WrapError(cmd, out, err)
+	}
+
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return pr, false, nil
+	}
+	if err := json.Unmarshal(out, &pr); err != nil {
+		return pr, false, fmt.Errorf("failed to parse pull request: %w", err)
+	}
+	return pr, true, nil
+}
+
+// PullRequestsForCommits looks up the pull request of each commit through
+// runBatch. Commits without a PR, or whose lookup failed, are absent from
+// the result and the failures are returned joined.
+func (c *Client) PullRequestsForCommits(commits []entity.Commit) (map[string]entity.PullRequest, error) {
+	var mu sync.Mutex
+	result := make(map[string]entity.PullRequest, len(commits))
+	err := c.runBatch(c.ctx, len(commits), func(_ context.Context, i int) error {
+		commit := commits[i]
+		pr, ok, err := c.PullRequestForCommit(commit.Repository, commit.SHA)
+		if err != nil || !ok {
+			return err
+		}
+		mu.Lock()
+		result[commit.SHA] = pr
+		mu.Unlock()
+		return nil
+	})
+	return result, err
+}
+
+// RateLimitStatus returns the search API quota, which commit searches count
+// against (a much smaller budget than the core API).
+func (c *Client) RateLimitStatus() (*entity.RateLimit, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "gh", "api", "rate_limit", "--jq", ".resources.search")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("gh api rate_limit timed out after %s", c.timeout)
+		}
+		return nil, WrapError(cmd, out, err)
+	}
+
+	var quota struct {
+		Limit     int   `json:"limit"`
+		Remaining int   `json:"remaining"`
+		Reset     int64 `json:"reset"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(out), &quota); err != nil {
+		return nil, fmt.Errorf("failed to parse rate limit: %w", err)
+	}
+
+	return &entity.RateLimit{
+		Limit:     quota.Limit,
+		Remaining: quota.Remaining,
+		Reset:     time.Unix(quota.Reset, 0),
+	}, nil
+}
+
+// LoginCommand returns gh auth login, to be run with the terminal attached.
+func LoginCommand() *exec.Cmd {
+	return exec.Command("gh", "auth", "login")
+}
+
+// dateFlag returns the gh search flag for a date field.
+func dateFlag(field entity.DateField) string {
+	if field == entity.DateFieldAuthor {
+		return "--author-date"
+	}
+	return "--committer-date"
+}
+
+// searchCommits runs gh search commits with the given arguments.
+func (c *Client) searchCommits(args ...string) ([]commitSearchItem, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(
-		ctx,
-		"gh",
-		"search",
-		"commits",
-		"--author", author,
-		"--committer-date", dateRange,
-		"--json", "repository,commit",
+	cmdArgs := append([]string{"search", "commits"}, args...)
+	cmdArgs = append(cmdArgs,
+		"--json", "repository,commit,sha",
 		"--limit", fmt.Sprintf("%d", c.limit),
 	)
+	cmd := exec.CommandContext(ctx, "gh", cmdArgs...)
 
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			return nil, fmt.Errorf("gh search commits timed out after %s", c.timeout)
 		}
-		return nil, fmt.Errorf("failed to fetch commits: %w\n%s", err, strings.TrimSpace(string(out)))
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil, fmt.Errorf("gh search commits cancelled")
+		}
+		return nil, fmt.Errorf("failed to fetch commits: %w", WrapError(cmd, out, err))
 	}
 
-	items, err := c.parseCommitSearchItems(out)
-	if err != nil {
-		return nil, err
-	}
+	return c.parseCommitSearchItems(out)
+}
 
+// buildCommitData groups search results by repository.
+func (c *Client) buildCommitData(items []commitSearchItem, field entity.DateField, coauthored bool) *entity.CommitData {
 	var warning string
-	if len(items) >= c.limit {
+	capped := len(items) >= c.limit
+	if capped {
 		warning = fmt.Sprintf("Results capped at %d commits by GitHub; summary may be incomplete.", c.limit)
 	}
 
 	commitMap := make(map[string][]entity.Commit)
+	dropped := 0
 	for _, item := range items {
 		repo := item.Repository.NameWithOwner
 		if repo == "" {
@@ -108,16 +412,29 @@ func (c *Client) FetchCommitsByAuthorAndDate(author, dateRange string) (*entity.
 			repo = item.Repository.Name
 		}
 
-		message := item.Commit.MessageHeadline
+		message := strings.TrimSpace(item.Commit.MessageHeadline)
 		if message == "" {
-			message = strings.Split(item.Commit.Message, "\n")[0]
+			message = firstNonBlankLine(item.Commit.Message)
 		}
 
-		if repo == "" || message == "" {
+		if repo == "" {
 			continue
 		}
+		if message == "" {
+			if c.dropEmptyMessages {
+				dropped++
+				continue
+			}
+			message = emptyMessagePlaceholder
+		}
 
-		commitMap[repo] = append(commitMap[repo], entity.Commit{Repository: repo, Message: message})
+		commitMap[repo] = append(commitMap[repo], entity.Commit{
+			Repository: repo,
+			Message:    message,
+			SHA:        item.Sha,
+			Date:       item.date(field),
+			Coauthored: coauthored,
+		})
 	}
 
 	var repoList []string
@@ -127,10 +444,54 @@ func (c *Client) FetchCommitsByAuthorAndDate(author, dateRange string) (*entity.
 	sort.Strings(repoList)
 
 	return &entity.CommitData{
-		Commits:  commitMap,
-		RepoList: repoList,
-		Warning:  warning,
-	}, nil
+		Commits:      commitMap,
+		RepoList:     repoList,
+		Warning:      warning,
+		DroppedEmpty: dropped,
+		Capped:       capped,
+	}
+}
+
+// firstNonBlankLine returns the first line of a message with non-whitespace
+// content, so messages starting with blank lines still get a headline.
+func firstNonBlankLine(message string) string {
+	for _, line := range strings.Split(message, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// hasCoauthorTrailer reports whether a commit message has a Co-authored-by
+// trailer whose email is one of the user's: a known address (lowercased
+// keys) or the login's noreply address. Mentions of the login elsewhere in
+// the trailer or message do not count.
+func hasCoauthorTrailer(message, login string, emails map[string]bool) bool {
+	noreply := "+" + strings.ToLower(login) + "@users.noreply.github.com"
+	for _, line := range strings.Split(message, "\n") {
+		email, ok := coauthorTrailerEmail(line)
+		if ok && (emails[email] || strings.HasSuffix(email, noreply)) {
+			return true
+		}
+	}
+	return false
+}
+
+// coauthorTrailerEmail extracts the lowercased email from a
+// "Co-authored-by: Name <email>" trailer line.
+func coauthorTrailerEmail(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	const prefix = "co-authored-by:"
+	if len(line) < len(prefix) || !strings.EqualFold(line[:len(prefix)], prefix) {
+		return "", false
+	}
+	start := strings.LastIndex(line, "<")
+	end := strings.LastIndex(line, ">")
+	if start < 0 || end <= start+1 {
+		return "", false
+	}
+	return strings.ToLower(strings.TrimSpace(line[start+1 : end])), true
 }
 
 func (c *Client) parseCommitSearchItems(data []byte) ([]commitSearchItem, error) {
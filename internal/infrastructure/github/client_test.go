@@ -0,0 +1,219 @@
+package github
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/DementevVV/commitsum/internal/domain/entity"
+	"github.com/DementevVV/commitsum/internal/domain/repository"
+)
+
+func TestHasCoauthorTrailer(t *testing.T) {
+	emails := map[string]bool{"dev@example.com": true}
+
+	tests := []struct {
+		name    string
+		message string
+		want    bool
+	}{
+		{
+			name:    "known email",
+			message: "Add login\n\nCo-authored-by: Dev <dev@example.com>",
+			want:    true,
+		},
+		{
+			name:    "known email, different case",
+			message: "Add login\n\nco-authored-by: Dev <Dev@Example.com>",
+			want:    true,
+		},
+		{
+			name:    "noreply address of the login",
+			message: "Add login\n\nCo-authored-by: Dev <12345+octocat@users.noreply.github.com>",
+			want:    true,
+		},
+		{
+			name:    "login in the trailer name only",
+			message: "Add login\n\nCo-authored-by: octocat fan <fan@example.com>",
+			want:    false,
+		},
+		{
+			name:    "noreply address of a longer login",
+			message: "Add login\n\nCo-authored-by: X <1+notoctocat@users.noreply.github.com>",
+			want:    false,
+		},
+		{
+			name:    "email outside a trailer",
+			message: "Thanks dev@example.com for the review",
+			want:    false,
+		},
+		{
+			name:    "trailer without email",
+			message: "Add login\n\nCo-authored-by: octocat",
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasCoauthorTrailer(tt.message, "octocat", emails); got != tt.want {
+				t.Errorf("hasCoauthorTrailer(%q) = %v, want %v", tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+// emptyMessageFixture is gh search output with one normal commit and one
+// whose headline and message are both empty.
+const emptyMessageFixture = `[
+  {"repository": {"nameWithOwner": "acme/api"}, "sha": "a1",
+   "commit": {"message": "Add login", "messageHeadline": "Add login"}},
+  {"repository": {"nameWithOwner": "acme/api"}, "sha": "a2",
+   "commit": {"message": "", "messageHeadline": ""}}
+]`
+
+func TestBuildCommitDataEmptyMessages(t *testing.T) {
+	tests := []struct {
+		name        string
+		drop        bool
+		wantCount   int
+		wantDropped int
+		wantLast    string
+	}{
+		{name: "kept with placeholder", drop: false, wantCount: 2, wantDropped: 0, wantLast: emptyMessagePlaceholder},
+		{name: "dropped", drop: true, wantCount: 1, wantDropped: 1, wantLast: "Add login"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient()
+			c.SetDropEmptyMessages(tt.drop)
+
+			items, err := c.parseCommitSearchItems([]byte(emptyMessageFixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			data := c.buildCommitData(items, entity.DateFieldCommitter, false)
+
+			commits := data.Commits["acme/api"]
+			if len(commits) != tt.wantCount {
+				t.Fatalf("got %d commits, want %d", len(commits), tt.wantCount)
+			}
+			if data.DroppedEmpty != tt.wantDropped {
+				t.Errorf("DroppedEmpty = %d, want %d", data.DroppedEmpty, tt.wantDropped)
+			}
+			if got := commits[len(commits)-1].Message; got != tt.wantLast {
+				t.Errorf("last message = %q, want %q", got, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestBuildCommitDataHeadlineFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		headline string
+		message  string
+		want     string
+	}{
+		{name: "headline", headline: "Add login", message: "Add login\n\nBody", want: "Add login"},
+		{name: "first line of message", message: "Add login\n\nBody", want: "Add login"},
+		{name: "leading newline", message: "\nAdd login\n\nBody", want: "Add login"},
+		{name: "leading blank lines", message: "\n  \n\t\n  Add login  \nBody", want: "Add login"},
+		{name: "blank headline", headline: "   ", message: "\r\nAdd login", want: "Add login"},
+		{name: "only whitespace", message: "\n \n", want: emptyMessagePlaceholder},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var item commitSearchItem
+			item.Repository.NameWithOwner = "acme/api"
+			item.Sha = "a1"
+			item.Commit.MessageHeadline = tt.headline
+			item.Commit.Message = tt.message
+
+			data := NewClient().buildCommitData([]commitSearchItem{item}, entity.DateFieldCommitter, false)
+			commits := data.Commits["acme/api"]
+			if len(commits) != 1 {
+				t.Fatalf("got %d commits, want the commit kept", len(commits))
+			}
+			if commits[0].Message != tt.want {
+				t.Errorf("message = %q, want %q", commits[0].Message, tt.want)
+			}
+		})
+	}
+}
+
+// fakeGH replaces PATH with a directory holding a gh shell script with the
+// given body, so a real gh cannot answer. An empty script leaves gh off PATH.
+func fakeGH(t *testing.T, script string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake gh is a shell script")
+	}
+	dir := t.TempDir()
+	if script != "" {
+		if err := os.WriteFile(filepath.Join(dir, "gh"), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir)
+}
+
+func TestGetUserErrors(t *testing.T) {
+	tests := []struct {
+		name             string
+		script           string
+		wantUser         string
+		wantAuth         bool
+		wantNotInstalled bool
+	}{
+		{
+			name:     "logged in",
+			script:   "echo octocat\n",
+			wantUser: "octocat",
+		},
+		{
+			name:             "not installed",
+			wantNotInstalled: true,
+		},
+		{
+			// The 401 text is not one IsAuthError knows; gh auth status decides.
+			name:     "installed but not logged in",
+			script:   "[ \"$1\" = auth ] && exit 1\necho 'HTTP 401: Requires authentication' >&2\nexit 1\n",
+			wantAuth: true,
+		},
+		{
+			name:   "logged in but the call failed",
+			script: "[ \"$1\" = auth ] && exit 0\necho 'HTTP 502: Bad Gateway' >&2\nexit 1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeGH(t, tt.script)
+
+			user, err := NewClient().GetUser()
+			if user != tt.wantUser {
+				t.Errorf("GetUser() = %q, want %q", user, tt.wantUser)
+			}
+			if tt.wantUser != "" {
+				if err != nil {
+					t.Fatalf("GetUser: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("GetUser succeeded, want an error")
+			}
+			if got := errors.Is(err, repository.ErrNotAuthenticated); got != tt.wantAuth {
+				t.Errorf("errors.Is(%v, ErrNotAuthenticated) = %v, want %v", err, got, tt.wantAuth)
+			}
+			if got := errors.Is(err, repository.ErrGHNotInstalled); got != tt.wantNotInstalled {
+				t.Errorf("errors.Is(%v, ErrGHNotInstalled) = %v, want %v", err, got, tt.wantNotInstalled)
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package github
 
 import (
+	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
+
+	"github.com/DementevVV/commitsum/internal/domain/repository"
 )
 
 // Error represents a GitHub CLI error.
@@ -17,6 +20,19 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("GitHub CLI error: %v\nCommand: %s\nOutput: %s", e.Err, e.Command, e.Output)
 }
 
+// Is lets errors.Is match authentication failures against
+// repository.ErrNotAuthenticated and a missing gh binary against
+// repository.ErrGHNotInstalled.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case repository.ErrNotAuthenticated:
+		return e.IsAuthError()
+	case repository.ErrGHNotInstalled:
+		return errors.Is(e.Err, exec.ErrNotFound)
+	}
+	return false
+}
+
 // IsAuthError checks if the error is an authentication issue.
 func (e *Error) IsAuthError() bool {
 	output := strings.ToLower(e.Output)
@@ -56,10 +72,13 @@ func WrapError(cmd *exec.Cmd, output []byte, err error) error {
 
 // GetUserFriendlyMessage returns a user-friendly error message.
 func GetUserFriendlyMessage(err error) string {
+	if errors.Is(err, repository.ErrGHNotInstalled) {
+		return "GitHub CLI (gh) is not installed. Please install it from https://cli.github.com/"
+	}
+	if errors.Is(err, repository.ErrNotAuthenticated) {
+		return "GitHub authentication required. Run 'gh auth login' to authenticate."
+	}
 	if ghErr, ok := err.(*Error); ok {
-		if ghErr.IsAuthError() {
-			return "GitHub authentication required. Run 'gh auth login' to authenticate."
-		}
 		if ghErr.IsRateLimitError() {
 			return "GitHub API rate limit exceeded. Please wait and try again later."
 		}
@@ -0,0 +1,77 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"github.com/DementevVV/commitsum/internal/domain/repository"
+)
+
+func TestErrorClassification(t *testing.T) {
+	cmd := exec.Command("gh", "api", "user")
+	exitErr := errors.New("exit status 1")
+
+	tests := []struct {
+		name             string
+		err              error
+		wantAuth         bool
+		wantNotInstalled bool
+		wantMessage      string
+	}{
+		{
+			name:             "gh missing",
+			err:              fmt.Errorf("%w: install it from https://cli.github.com/", repository.ErrGHNotInstalled),
+			wantNotInstalled: true,
+			wantMessage:      "GitHub CLI (gh) is not installed. Please install it from https://cli.github.com/",
+		},
+		{
+			name:             "gh missing from a wrapped command",
+			err:              WrapError(cmd, nil, exec.ErrNotFound),
+			wantNotInstalled: true,
+			wantMessage:      "GitHub CLI (gh) is not installed. Please install it from https://cli.github.com/",
+		},
+		{
+			name:        "installed but not logged in",
+			err:         fmt.Errorf("%w: gh is installed but not logged in, run 'gh auth login'", repository.ErrNotAuthenticated),
+			wantAuth:    true,
+			wantMessage: "GitHub authentication required. Run 'gh auth login' to authenticate.",
+		},
+		{
+			name:        "auth failure in gh output",
+			err:         WrapError(cmd, []byte("To get started with GitHub CLI, please run: gh auth login\nYou are not logged in"), exitErr),
+			wantAuth:    true,
+			wantMessage: "GitHub authentication required. Run 'gh auth login' to authenticate.",
+		},
+		{
+			name:        "rate limit",
+			err:         WrapError(cmd, []byte("API rate limit exceeded for user"), exitErr),
+			wantMessage: "GitHub API rate limit exceeded. Please wait and try again later.",
+		},
+		{
+			name:        "network",
+			err:         WrapError(cmd, []byte("dial tcp: lookup api.github.com: no such host (dns)"), exitErr),
+			wantMessage: "Network error. Please check your internet connection and try again.",
+		},
+		{
+			name:        "other",
+			err:         WrapError(cmd, []byte("HTTP 404: Not Found"), exitErr),
+			wantMessage: "Error: GitHub CLI error: exit status 1\nCommand: gh api user\nOutput: HTTP 404: Not Found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, repository.ErrNotAuthenticated); got != tt.wantAuth {
+				t.Errorf("errors.Is(err, ErrNotAuthenticated) = %v, want %v", got, tt.wantAuth)
+			}
+			if got := errors.Is(tt.err, repository.ErrGHNotInstalled); got != tt.wantNotInstalled {
+				t.Errorf("errors.Is(err, ErrGHNotInstalled) = %v, want %v", got, tt.wantNotInstalled)
+			}
+			if got := GetUserFriendlyMessage(tt.err); got != tt.wantMessage {
+				t.Errorf("GetUserFriendlyMessage() = %q, want %q", got, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Package jsonfile reads and writes the small JSON map files commitsum keeps
+// next to its config file.
+package jsonfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/DementevVV/commitsum/internal/infrastructure/atomicfile"
+	"github.com/DementevVV/commitsum/internal/infrastructure/config"
+)
+
+// Path returns the path of the file name in the config file's directory, so
+// that --config moves it along with the config.
+func Path(name string) (string, error) {
+	configPath, err := config.Path()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), name), nil
+}
+
+// LoadMap reads a JSON object from path; a missing file is an empty map.
+func LoadMap[V any](path string) (map[string]V, error) {
+	m := make(map[string]V)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// SaveMap writes m to path atomically, creating the directory if needed.
+func SaveMap[V any](path string, m map[string]V) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(path, data, 0644)
+}
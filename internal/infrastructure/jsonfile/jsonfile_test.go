@@ -0,0 +1,87 @@
+package jsonfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/DementevVV/commitsum/internal/infrastructure/config"
+)
+
+func TestPathFollowsConfig(t *testing.T) {
+	dir := t.TempDir()
+	config.SetPath(filepath.Join(dir, "work.json"))
+	t.Cleanup(func() { config.SetPath("") })
+
+	got, err := Path("seen.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "seen.json"); got != want {
+		t.Errorf("Path() = %q, want %q", got, want)
+	}
+}
+
+func TestMapRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "sums.json")
+
+	empty, err := LoadMap[string](path)
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("LoadMap of a missing file = %v, %v, want an empty map", empty, err)
+	}
+
+	want := map[string]string{"2024-03-01..2024-03-07": "abc", "2024-03-08..2024-03-14": "def"}
+	if err := SaveMap(path, want); err != nil {
+		t.Fatalf("SaveMap: %v", err)
+	}
+	got, err := LoadMap[string](path)
+	if err != nil {
+		t.Fatalf("LoadMap: %v", err)
+	}
+	if len(got) != len(want) || got["2024-03-01..2024-03-07"] != "abc" || got["2024-03-08..2024-03-14"] != "def" {
+		t.Errorf("LoadMap() = %v, want %v", got, want)
+	}
+	if tmps, _ := filepath.Glob(path + ".tmp*"); len(tmps) != 0 {
+		t.Errorf("temporary files left behind: %v", tmps)
+	}
+}
+
+func TestSaveMapConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checksums.json")
+
+	// Concurrent writers (cron plus the TUI) must not share a temp file.
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := SaveMap(path, map[string]int{"run": i}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	got, err := LoadMap[int](path)
+	if err != nil {
+		t.Fatalf("LoadMap after concurrent saves: %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("got %v, want a single complete write", got)
+	}
+	if tmps, _ := filepath.Glob(path + ".tmp*"); len(tmps) != 0 {
+		t.Errorf("temporary files left behind: %v", tmps)
+	}
+}
+
+func TestLoadMapCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sums.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadMap[string](path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("LoadMap() error = %v, want a parse error naming %s", err, path)
+	}
+}
@@ -0,0 +1,160 @@
+// Package localgit reads commits from local git checkouts.
+package localgit
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DementevVV/commitsum/internal/domain/entity"
+	"github.com/DementevVV/commitsum/internal/domain/repository"
+	"github.com/DementevVV/commitsum/internal/infrastructure/logger"
+)
+
+// fieldSeparator separates fields in the git log format (ASCII unit separator).
+const fieldSeparator = "\x1f"
+
+// Client runs git log in local repositories.
+type Client struct {
+	timeout time.Duration
+}
+
+// Ensure Client implements LocalGitRepository.
+var _ repository.LocalGitRepository = (*Client)(nil)
+
+// NewClient creates a new local git client.
+func NewClient() *Client {
+	return &Client{timeout: 10 * time.Second}
+}
+
+// FetchCommits returns commits authored by each repository's configured
+// user.email between two dates, across all local branches, matching the range
+// against the given date field. Paths that are not usable git repositories
+// are skipped and reported in the warning.
+func (c *Client) FetchCommits(paths []string, startDate, endDate string, field entity.DateField) (*entity.CommitData, error) {
+	data := &entity.CommitData{Commits: make(map[string][]entity.Commit)}
+
+	var skipped []string
+	for _, path := range paths {
+		repo, commits, err := c.repoCommits(path, startDate, endDate, field)
+		if err != nil {
+			logger.Warn("Skipping local repository", "path", path, "error", err.Error())
+			skipped = append(skipped, path)
+			continue
+		}
+		if len(commits) > 0 {
+			data.Commits[repo] = append(data.Commits[repo], commits...)
+		}
+	}
+
+	for repo := range data.Commits {
+		data.RepoList = append(data.RepoList, repo)
+	}
+	sort.Strings(data.RepoList)
+
+	if len(skipped) > 0 {
+		data.Warning = "Skipped local paths (not a git repository or no user.email, see log): " + strings.Join(skipped, ", ")
+	}
+	return data, nil
+}
+
+// repoCommits reads one repository's commits for the range.
+func (c *Client) repoCommits(path, startDate, endDate string, field entity.DateField) (string, []entity.Commit, error) {
+	if _, err := c.git(path, "rev-parse", "--git-dir"); err != nil {
+		return "", nil, fmt.Errorf("not a git repository")
+	}
+
+	email, err := c.git(path, "config", "user.email")
+	if err != nil || email == "" {
+		return "", nil, fmt.Errorf("user.email is not configured")
+	}
+
+	// git log limits by committer date only. A commit is committed no earlier
+	// than it is authored, so --since still narrows author-date queries; the
+	// range itself is then checked against the author date below.
+	args := []string{"log", "--all", "--author=" + email, "--since=" + startDate + " 00:00:00"}
+	dateFormat := "%cI"
+	if field == entity.DateFieldAuthor {
+		dateFormat = "%aI"
+	} else {
+		args = append(args, "--until="+endDate+" 23:59:59")
+	}
+	args = append(args, "--format=%H"+fieldSeparator+dateFormat+fieldSeparator+"%s")
+
+	out, err := c.git(path, args...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	repo := c.repoName(path)
+	var commits []entity.Commit
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, fieldSeparator, 3)
+		if len(fields) != 3 || strings.TrimSpace(fields[2]) == "" {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, fields[1])
+		if field == entity.DateFieldAuthor {
+			if day := date.Local().Format(entity.DateLayout); day < startDate || day > endDate {
+				continue
+			}
+		}
+		commits = append(commits, entity.Commit{
+			Repository: repo,
+			Message:    strings.TrimSpace(fields[2]),
+			SHA:        fields[0],
+			Date:       date,
+			Local:      true,
+		})
+	}
+	return repo, commits, nil
+}
+
+// repoName returns "owner/name" from the origin remote, so local commits merge
+// with the same repository's GitHub results, or the directory name otherwise.
+func (c *Client) repoName(path string) string {
+	url, err := c.git(path, "remote", "get-url", "origin")
+	if err == nil {
+		if name := ownerNameFromURL(url); name != "" {
+			return name
+		}
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Base(path)
+	}
+	return filepath.Base(abs)
+}
+
+// ownerNameFromURL extracts "owner/name" from an HTTPS or SSH remote URL.
+func ownerNameFromURL(url string) string {
+	url = strings.TrimSuffix(strings.TrimSpace(url), ".git")
+	if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+	} else if _, rest, ok := strings.Cut(url, ":"); ok {
+		url = "host/" + rest // git@github.com:owner/name
+	}
+
+	parts := strings.Split(url, "/")
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[len(parts)-2] + "/" + parts[len(parts)-1]
+}
+
+// git runs a git command in a repository and returns its trimmed output.
+func (c *Client) git(path string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", path}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,202 @@
+package localgit
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/DementevVV/commitsum/internal/domain/entity"
+)
+
+// gitRepo is a temporary git repository for tests.
+type gitRepo struct {
+	t   *testing.T
+	dir string
+}
+
+// newGitRepo initializes a repository committing as me@example.com. Global
+// and system git config are ignored so the host setup cannot leak in, and
+// dates are read in UTC.
+func newGitRepo(t *testing.T) *gitRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("TZ", "UTC")
+
+	r := &gitRepo{t: t, dir: t.TempDir()}
+	r.git(nil, "init", "-q", "-b", "main")
+	r.git(nil, "config", "user.name", "Me")
+	r.git(nil, "config", "user.email", "me@example.com")
+	r.git(nil, "config", "commit.gpgsign", "false")
+	return r
+}
+
+// git runs a git command in the repository with extra environment variables.
+func (r *gitRepo) git(env []string, args ...string) string {
+	r.t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", r.dir}, args...)...)
+	cmd.Env = append(os.Environ(), env...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// commit records an empty commit dated date (RFC 3339) by author ("Name
+// <email>"), or by the configured user when author is empty.
+func (r *gitRepo) commit(message, date, author string) {
+	r.t.Helper()
+	env := []string{"GIT_AUTHOR_DATE=" + date, "GIT_COMMITTER_DATE=" + date}
+	args := []string{"commit", "-q", "--allow-empty", "-m", message}
+	if author != "" {
+		args = append(args, "--author="+author)
+	}
+	r.git(env, args...)
+}
+
+func TestFetchCommits(t *testing.T) {
+	repo := newGitRepo(t)
+	repo.git(nil, "remote", "add", "origin", "git@github.com:acme/api.git")
+
+	repo.commit("Before the range", "2026-01-31T12:00:00Z", "")
+	repo.commit("Add login", "2026-02-01T09:00:00Z", "")
+	repo.commit("Someone else's change", "2026-02-01T10:00:00Z", "Other <other@example.com>")
+	repo.git(nil, "checkout", "-q", "-b", "feature")
+	repo.commit("Draft on a branch", "2026-02-02T11:00:00Z", "")
+	repo.git(nil, "checkout", "-q", "main")
+	repo.commit("After the range", "2026-02-04T08:00:00Z", "")
+
+	notGit := t.TempDir()
+
+	data, err := NewClient().FetchCommits([]string{repo.dir, notGit}, "2026-02-01", "2026-02-02", entity.DateFieldCommitter)
+	if err != nil {
+		t.Fatalf("FetchCommits: %v", err)
+	}
+
+	if want := []string{"acme/api"}; !slices.Equal(data.RepoList, want) {
+		t.Fatalf("RepoList = %v, want %v", data.RepoList, want)
+	}
+
+	var messages []string
+	for _, c := range data.Commits["acme/api"] {
+		if !c.Local {
+			t.Errorf("commit %q is not tagged local", c.Message)
+		}
+		if c.Repository != "acme/api" || c.SHA == "" || c.Date.IsZero() {
+			t.Errorf("commit %+v is missing fields", c)
+		}
+		messages = append(messages, c.Message)
+	}
+	slices.Sort(messages)
+	if want := []string{"Add login", "Draft on a branch"}; !slices.Equal(messages, want) {
+		t.Errorf("messages = %v, want %v", messages, want)
+	}
+
+	if !strings.Contains(data.Warning, notGit) {
+		t.Errorf("Warning = %q, want it to name the skipped path %s", data.Warning, notGit)
+	}
+}
+
+func TestFetchCommitsWithoutRemote(t *testing.T) {
+	repo := newGitRepo(t)
+	repo.commit("Add login", "2026-02-01T09:00:00Z", "")
+
+	data, err := NewClient().FetchCommits([]string{repo.dir}, "2026-02-01", "2026-02-01", entity.DateFieldCommitter)
+	if err != nil {
+		t.Fatalf("FetchCommits: %v", err)
+	}
+
+	name := filepath.Base(repo.dir)
+	if len(data.Commits[name]) != 1 {
+		t.Errorf("Commits = %v, want one commit under the directory name %q", data.Commits, name)
+	}
+	if data.Warning != "" {
+		t.Errorf("Warning = %q, want none", data.Warning)
+	}
+}
+
+func TestFetchCommitsWithoutEmail(t *testing.T) {
+	repo := newGitRepo(t)
+	repo.commit("Add login", "2026-02-01T09:00:00Z", "")
+	repo.git(nil, "config", "--unset", "user.email")
+
+	data, err := NewClient().FetchCommits([]string{repo.dir}, "2026-02-01", "2026-02-01", entity.DateFieldCommitter)
+	if err != nil {
+		t.Fatalf("FetchCommits: %v", err)
+	}
+	if len(data.Commits) != 0 {
+		t.Errorf("Commits = %v, want none", data.Commits)
+	}
+	if !strings.Contains(data.Warning, repo.dir) {
+		t.Errorf("Warning = %q, want it to name %s", data.Warning, repo.dir)
+	}
+}
+
+func TestOwnerNameFromURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://github.com/acme/api.git", "acme/api"},
+		{"https://github.com/acme/api", "acme/api"},
+		{"git@github.com:acme/api.git", "acme/api"},
+		{"ssh://git@github.com/acme/api.git", "acme/api"},
+		{"  https://github.com/acme/api.git\n", "acme/api"},
+		{"api", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ownerNameFromURL(tt.url); got != tt.want {
+			t.Errorf("ownerNameFromURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestFetchCommitsDateField(t *testing.T) {
+	repo := newGitRepo(t)
+	// Authored on Feb 1, committed (e.g. rebased) on Feb 3.
+	repo.git([]string{"GIT_AUTHOR_DATE=2026-02-01T10:00:00Z", "GIT_COMMITTER_DATE=2026-02-03T10:00:00Z"},
+		"commit", "-q", "--allow-empty", "-m", "Rebased fix")
+
+	tests := []struct {
+		name       string
+		field      entity.DateField
+		start, end string
+		want       int
+		wantDate   string
+	}{
+		{name: "committer date in range", field: entity.DateFieldCommitter, start: "2026-02-03", end: "2026-02-03", want: 1, wantDate: "2026-02-03"},
+		{name: "committer date out of range", field: entity.DateFieldCommitter, start: "2026-02-01", end: "2026-02-01", want: 0},
+		{name: "author date in range", field: entity.DateFieldAuthor, start: "2026-02-01", end: "2026-02-01", want: 1, wantDate: "2026-02-01"},
+		{name: "author date out of range", field: entity.DateFieldAuthor, start: "2026-02-03", end: "2026-02-03", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := NewClient().FetchCommits([]string{repo.dir}, tt.start, tt.end, tt.field)
+			if err != nil {
+				t.Fatalf("FetchCommits: %v", err)
+			}
+			var commits []entity.Commit
+			for _, repoCommits := range data.Commits {
+				commits = append(commits, repoCommits...)
+			}
+			if len(commits) != tt.want {
+				t.Fatalf("got %d commits, want %d", len(commits), tt.want)
+			}
+			if tt.want > 0 {
+				if got := commits[0].Date.Format(entity.DateLayout); got != tt.wantDate {
+					t.Errorf("commit date = %s, want %s", got, tt.wantDate)
+				}
+			}
+		})
+	}
+}
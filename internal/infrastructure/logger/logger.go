@@ -85,6 +85,14 @@ func Init(level Level, version, buildTime string) error {
 	return nil
 }
 
+// Path returns the current log file path, or "" if logging is not initialized.
+func Path() string {
+	if defaultLogger == nil || defaultLogger.file == nil {
+		return ""
+	}
+	return defaultLogger.file.Name()
+}
+
 // Disable disables logging (for tests).
 func Disable() {
 	if defaultLogger != nil {
@@ -99,6 +107,17 @@ func Enable() {
 	}
 }
 
+// SetOutput sends messages at level and above to w instead of the log file
+// and returns a function restoring the previous logger (for tests).
+func SetOutput(w io.Writer, level Level) (restore func()) {
+	prev := defaultLogger
+	defaultLogger = &Logger{
+		logger: log.New(w, "", 0),
+		level:  level,
+	}
+	return func() { defaultLogger = prev }
+}
+
 // Close closes the logger and file.
 func Close() error {
 	if defaultLogger != nil && defaultLogger.file != nil {
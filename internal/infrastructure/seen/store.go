@@ -0,0 +1,103 @@
+// Package seen records which commits have already been viewed.
+package seen
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DementevVV/commitsum/internal/infrastructure/jsonfile"
+)
+
+// maxKeys bounds how many date ranges are remembered; the least recently
+// updated ones are dropped first.
+const maxKeys = 60
+
+// entry is the seen set for one date range.
+type entry struct {
+	SHAs    []string  `json:"shas"`
+	Updated time.Time `json:"updated"`
+}
+
+// FileStore keeps seen commit SHAs in a single JSON file.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore creates a store in seen.json next to the config file.
+func NewFileStore() (*FileStore, error) {
+	path, err := jsonfile.Path("seen.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate seen store: %w", err)
+	}
+	return &FileStore{path: path}, nil
+}
+
+// Seen returns the SHAs recorded for key.
+func (s *FileStore) Seen(key string) (map[string]bool, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := jsonfile.LoadMap[entry](s.path)
+	if err != nil {
+		return nil, false, err
+	}
+	e, ok := entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	shas := make(map[string]bool, len(e.SHAs))
+	for _, sha := range e.SHAs {
+		shas[sha] = true
+	}
+	return shas, true, nil
+}
+
+// MarkSeen adds shas to the set recorded for key.
+func (s *FileStore) MarkSeen(key string, shas []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := jsonfile.LoadMap[entry](s.path)
+	if err != nil {
+		return err
+	}
+
+	set := make(map[string]bool)
+	for _, sha := range entries[key].SHAs {
+		set[sha] = true
+	}
+	for _, sha := range shas {
+		if sha != "" {
+			set[sha] = true
+		}
+	}
+	merged := make([]string, 0, len(set))
+	for sha := range set {
+		merged = append(merged, sha)
+	}
+	sort.Strings(merged)
+	entries[key] = entry{SHAs: merged, Updated: time.Now()}
+
+	prune(entries)
+	return jsonfile.SaveMap(s.path, entries)
+}
+
+// prune drops the least recently updated entries beyond maxKeys.
+func prune(entries map[string]entry) {
+	if len(entries) <= maxKeys {
+		return
+	}
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return entries[keys[i]].Updated.After(entries[keys[j]].Updated)
+	})
+	for _, key := range keys[maxKeys:] {
+		delete(entries, key)
+	}
+}
@@ -0,0 +1,40 @@
+package seen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DementevVV/commitsum/internal/infrastructure/config"
+)
+
+func TestFileStoreBesideConfig(t *testing.T) {
+	dir := t.TempDir()
+	config.SetPath(filepath.Join(dir, "work.json"))
+	t.Cleanup(func() { config.SetPath("") })
+
+	store, err := NewFileStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.MarkSeen("2024-03-01..2024-03-01", []string{"a1", "", "a2"}); err != nil {
+		t.Fatalf("MarkSeen: %v", err)
+	}
+	if err := store.MarkSeen("2024-03-01..2024-03-01", []string{"a3"}); err != nil {
+		t.Fatalf("MarkSeen: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "seen.json")); err != nil {
+		t.Fatalf("store is not next to the config file: %v", err)
+	}
+
+	got, ok, err := store.Seen("2024-03-01..2024-03-01")
+	if err != nil || !ok {
+		t.Fatalf("Seen() = %v, %v, %v", got, ok, err)
+	}
+	if len(got) != 3 || !got["a1"] || !got["a2"] || !got["a3"] {
+		t.Errorf("Seen() = %v, want a1, a2 and a3", got)
+	}
+	if _, ok, _ := store.Seen("2024-03-02..2024-03-02"); ok {
+		t.Error("Seen() found an unrecorded range")
+	}
+}
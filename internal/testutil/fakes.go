@@ -0,0 +1,302 @@
+// Package testutil provides in-memory fakes of the domain repositories for
+// tests that exercise the use cases and UI without gh, a cache directory or
+// a system clipboard.
+package testutil
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DementevVV/commitsum/internal/domain/entity"
+	"github.com/DementevVV/commitsum/internal/domain/repository"
+)
+
+// FetchCall records one commit search made through FakeGitHub.
+type FetchCall struct {
+	Author    string
+	DateRange string
+	Field     entity.DateField
+}
+
+// FakeGitHub serves fixed commits for every search.
+type FakeGitHub struct {
+	mu sync.Mutex
+
+	// User is returned by GetUser unless UserErr is set.
+	User    string
+	UserErr error
+	// Commits are returned for every author search unless Err is set.
+	Commits map[string][]entity.Commit
+	Err     error
+	// Capped marks fetched data as hitting the search result cap.
+	Capped bool
+	// Coauthored are returned by FetchCoauthoredCommits.
+	Coauthored map[string][]entity.Commit
+	// Count is returned by CountCommits; a negative value means the number
+	// of commits in Commits.
+	Count int
+	// OnBranch and PRs answer the per-commit enrichment calls by SHA.
+	OnBranch map[string]bool
+	PRs      map[string]entity.PullRequest
+
+	// Fetches records every author search in call order.
+	Fetches []FetchCall
+	// Counts is how many times CountCommits was called.
+	Counts int
+}
+
+// Ensure FakeGitHub implements GitHubRepository.
+var _ repository.GitHubRepository = (*FakeGitHub)(nil)
+
+// NewFakeGitHub returns a fake for user "octocat" serving commits.
+func NewFakeGitHub(commits map[string][]entity.Commit) *FakeGitHub {
+	return &FakeGitHub{User: "octocat", Commits: commits, Count: -1}
+}
+
+// GetUser returns the configured user.
+func (f *FakeGitHub) GetUser() (string, error) {
+	return f.User, f.UserErr
+}
+
+// FetchCommitsByAuthorAndDate returns a copy of the configured commits.
+func (f *FakeGitHub) FetchCommitsByAuthorAndDate(author, dateRange string, field entity.DateField) (*entity.CommitData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.Fetches = append(f.Fetches, FetchCall{Author: author, DateRange: dateRange, Field: field})
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	data := NewCommitData(f.Commits)
+	data.SearchTotal = CountAll(f.Commits)
+	data.Capped = f.Capped
+	return data, nil
+}
+
+// FetchCoauthoredCommits returns a copy of the configured co-authored commits.
+func (f *FakeGitHub) FetchCoauthoredCommits(author, dateRange string, field entity.DateField) (*entity.CommitData, error) {
+	return NewCommitData(f.Coauthored), nil
+}
+
+// CountCommits returns Count, or the number of configured commits.
+func (f *FakeGitHub) CountCommits(author, dateRange string, field entity.DateField) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.Counts++
+	if f.Count >= 0 {
+		return f.Count, nil
+	}
+	return CountAll(f.Commits), nil
+}
+
+// CommitsOnBranch answers from OnBranch; unknown SHAs count as on the branch.
+func (f *FakeGitHub) CommitsOnBranch(commits []entity.Commit, branch string) (map[string]bool, error) {
+	result := make(map[string]bool, len(commits))
+	for _, commit := range commits {
+		on, ok := f.OnBranch[commit.SHA]
+		result[commit.SHA] = on || !ok
+	}
+	return result, nil
+}
+
+// PullRequestsForCommits answers from PRs.
+func (f *FakeGitHub) PullRequestsForCommits(commits []entity.Commit) (map[string]entity.PullRequest, error) {
+	result := make(map[string]entity.PullRequest)
+	for _, commit := range commits {
+		if pr, ok := f.PRs[commit.SHA]; ok {
+			result[commit.SHA] = pr
+		}
+	}
+	return result, nil
+}
+
+// RateLimitStatus reports a healthy quota.
+func (f *FakeGitHub) RateLimitStatus() (*entity.RateLimit, error) {
+	return &entity.RateLimit{Limit: 30, Remaining: 30, Reset: time.Now().Add(time.Minute)}, nil
+}
+
+// FetchCount returns how many author searches were made.
+func (f *FakeGitHub) FetchCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.Fetches)
+}
+
+// FakeCache is an in-memory CacheRepository without expiry.
+type FakeCache struct {
+	mu      sync.Mutex
+	entries map[string]*entity.CommitData
+	ttls    map[string]time.Duration
+}
+
+// Ensure FakeCache implements CacheRepository.
+var _ repository.CacheRepository = (*FakeCache)(nil)
+
+// NewFakeCache returns an empty cache.
+func NewFakeCache() *FakeCache {
+	return &FakeCache{
+		entries: make(map[string]*entity.CommitData),
+		ttls:    make(map[string]time.Duration),
+	}
+}
+
+func cacheKey(author, dateRange string) string {
+	return author + "|" + dateRange
+}
+
+// GetCommits returns a copy of the stored entry.
+func (c *FakeCache) GetCommits(author, dateRange string) (*entity.CommitData, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, ok := c.entries[cacheKey(author, dateRange)]
+	if !ok {
+		return nil, false, nil
+	}
+	return copyCommitData(data), true, nil
+}
+
+// PeekRepoList returns the stored repository list.
+func (c *FakeCache) PeekRepoList(author, dateRange string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if data, ok := c.entries[cacheKey(author, dateRange)]; ok {
+		return slices.Clone(data.RepoList)
+	}
+	return nil
+}
+
+// SetCommits stores a copy of data and records ttl; entries never expire.
+func (c *FakeCache) SetCommits(author, dateRange string, data *entity.CommitData, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[cacheKey(author, dateRange)] = copyCommitData(data)
+	c.ttls[cacheKey(author, dateRange)] = ttl
+	return nil
+}
+
+// TTL returns the ttl passed when the entry was stored.
+func (c *FakeCache) TTL(author, dateRange string) (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ttl, ok := c.ttls[cacheKey(author, dateRange)]
+	return ttl, ok
+}
+
+// Invalidate removes every entry of author.
+func (c *FakeCache) Invalidate(author string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if len(key) > len(author) && key[:len(author)+1] == author+"|" {
+			delete(c.entries, key)
+			delete(c.ttls, key)
+		}
+	}
+	return nil
+}
+
+// Clear removes all entries.
+func (c *FakeCache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*entity.CommitData)
+	c.ttls = make(map[string]time.Duration)
+	return nil
+}
+
+// Dir returns a placeholder directory.
+func (c *FakeCache) Dir() string {
+	return "/fake/cache"
+}
+
+// Len returns the number of stored entries.
+func (c *FakeCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// FakeClipboard records copied text.
+type FakeClipboard struct {
+	mu sync.Mutex
+	// Err is returned by Copy when set.
+	Err    error
+	copies []string
+}
+
+// Ensure FakeClipboard implements ClipboardRepository.
+var _ repository.ClipboardRepository = (*FakeClipboard)(nil)
+
+// Copy records text unless Err is set.
+func (c *FakeClipboard) Copy(text string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.Err != nil {
+		return c.Err
+	}
+	c.copies = append(c.copies, text)
+	return nil
+}
+
+// IsAvailable reports true.
+func (c *FakeClipboard) IsAvailable() bool {
+	return true
+}
+
+// Copies returns everything copied so far.
+func (c *FakeClipboard) Copies() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.copies)
+}
+
+// Last returns the most recent copy, or "".
+func (c *FakeClipboard) Last() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.copies) == 0 {
+		return ""
+	}
+	return c.copies[len(c.copies)-1]
+}
+
+// NewCommitData builds CommitData from a copy of commits, with a sorted
+// repository list.
+func NewCommitData(commits map[string][]entity.Commit) *entity.CommitData {
+	data := &entity.CommitData{Commits: make(map[string][]entity.Commit, len(commits))}
+	for repo, repoCommits := range commits {
+		data.Commits[repo] = slices.Clone(repoCommits)
+		data.RepoList = append(data.RepoList, repo)
+	}
+	sort.Strings(data.RepoList)
+	return data
+}
+
+// CountAll returns the number of commits across all repositories.
+func CountAll(commits map[string][]entity.Commit) int {
+	total := 0
+	for _, repoCommits := range commits {
+		total += len(repoCommits)
+	}
+	return total
+}
+
+func copyCommitData(data *entity.CommitData) *entity.CommitData {
+	copied := *data
+	copied.Commits = make(map[string][]entity.Commit, len(data.Commits))
+	for repo, commits := range data.Commits {
+		copied.Commits[repo] = slices.Clone(commits)
+	}
+	copied.RepoList = slices.Clone(data.RepoList)
+	return &copied
+}
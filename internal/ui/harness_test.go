@@ -0,0 +1,227 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/DementevVV/commitsum/internal/domain/entity"
+	"github.com/DementevVV/commitsum/internal/infrastructure/config"
+	"github.com/DementevVV/commitsum/internal/testutil"
+	"github.com/DementevVV/commitsum/internal/usecase"
+)
+
+// cmdTimeout bounds how long the harness waits for a command. Commands
+// still running afterwards (timers such as message expiry) are dropped.
+const cmdTimeout = 50 * time.Millisecond
+
+// harness drives a Model headlessly: keys and messages go through Update and
+// the returned commands are run, feeding their messages back in.
+type harness struct {
+	t         testing.TB
+	m         *Model
+	github    *testutil.FakeGitHub
+	cache     *testutil.FakeCache
+	clipboard *testutil.FakeClipboard
+	commitUC  *usecase.CommitUseCase
+	// quit is set once a command returned tea.QuitMsg.
+	quit bool
+}
+
+// newHarness builds a Model on fakes serving commits for every range. The
+// home directory (holding the config file) and the working directory (for
+// saved exports) are temporary.
+func newHarness(t testing.TB, commits map[string][]entity.Commit, configure ...func(*config.Config)) *harness {
+	t.Helper()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+
+	cfg := config.Default()
+	for _, fn := range configure {
+		fn(&cfg)
+	}
+
+	gh := testutil.NewFakeGitHub(commits)
+	cache := testutil.NewFakeCache()
+	clip := &testutil.FakeClipboard{}
+	commitUC := usecase.NewCommitUseCase(gh, cache)
+	exportUC := usecase.NewExportUseCase()
+
+	return &harness{
+		t:         t,
+		m:         NewModel(cfg, commitUC, exportUC, clip),
+		github:    gh,
+		cache:     cache,
+		clipboard: clip,
+		commitUC:  commitUC,
+	}
+}
+
+// keyMsg converts a key name as returned by tea.KeyMsg.String() back into a
+// key message.
+func keyMsg(key string) tea.KeyMsg {
+	switch key {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case " ", "space":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	case "backspace":
+		return tea.KeyMsg{Type: tea.KeyBackspace}
+	case "up":
+		return tea.KeyMsg{Type: tea.KeyUp}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "left":
+		return tea.KeyMsg{Type: tea.KeyLeft}
+	case "ctrl+s":
+		return tea.KeyMsg{Type: tea.KeyCtrlS}
+	default:
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+}
+
+// press sends each key in turn.
+func (h *harness) press(keys ...string) {
+	h.t.Helper()
+	for _, key := range keys {
+		h.send(keyMsg(key))
+	}
+}
+
+// typeText sends text as one rune key per character.
+func (h *harness) typeText(text string) {
+	h.t.Helper()
+	for _, r := range text {
+		h.send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+// send delivers msg and runs the resulting command.
+func (h *harness) send(msg tea.Msg) {
+	h.t.Helper()
+	_, cmd := h.m.Update(msg)
+	h.run(cmd)
+}
+
+// run executes cmd and feeds its message back into the model. Batches run
+// each command; spinner ticks and message expiry are dropped so the loop
+// ends.
+func (h *harness) run(cmd tea.Cmd) {
+	h.t.Helper()
+	if cmd == nil {
+		return
+	}
+
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+	var msg tea.Msg
+	select {
+	case msg = <-done:
+	case <-time.After(cmdTimeout):
+		return
+	}
+
+	switch msg := msg.(type) {
+	case nil, spinner.TickMsg, messageExpiredMsg:
+	case tea.BatchMsg:
+		for _, c := range msg {
+			h.run(c)
+		}
+	case tea.QuitMsg:
+		h.quit = true
+	default:
+		h.send(msg)
+	}
+}
+
+// load delivers a commitsLoadedMsg as a finished fetch would, from the
+// loading screen that receives it.
+func (h *harness) load(commits map[string][]entity.Commit) {
+	h.t.Helper()
+	h.m.loading = true
+	h.m.screen = screenLoading
+	data := testutil.NewCommitData(commits)
+	h.send(commitsLoadedMsg{
+		commits:  data.Commits,
+		repoList: data.RepoList,
+	})
+}
+
+// wantScreen fails the test unless the model shows screen.
+func (h *harness) wantScreen(screen screenState) {
+	h.t.Helper()
+	if h.m.screen != screen {
+		h.t.Fatalf("screen = %v, want %v", h.m.screen, screen)
+	}
+}
+
+// wantCursor fails the test unless the repo list cursor is at i.
+func (h *harness) wantCursor(i int) {
+	h.t.Helper()
+	if h.m.cursor != i {
+		h.t.Fatalf("cursor = %d, want %d", h.m.cursor, i)
+	}
+}
+
+// selectedRepos returns the selected repos in display order.
+func (h *harness) selectedRepos() []string {
+	var repos []string
+	for _, repo := range h.m.getDisplayRepos() {
+		if h.m.selected[repo] {
+			repos = append(repos, repo)
+		}
+	}
+	return repos
+}
+
+// presetIndex returns the position of a date range preset.
+func presetIndex(t testing.TB, key string) int {
+	t.Helper()
+	for i, preset := range entity.DateRangePresets {
+		if preset.Key == key {
+			return i
+		}
+	}
+	t.Fatalf("no preset %q", key)
+	return -1
+}
+
+// sampleCommits is a small fixture across three repositories.
+func sampleCommits() map[string][]entity.Commit {
+	return map[string][]entity.Commit{
+		"acme/api": {
+			{Repository: "acme/api", Message: "Add login", SHA: "a1"},
+			{Repository: "acme/api", Message: "Fix logout", SHA: "a2"},
+		},
+		"acme/web": {
+			{Repository: "acme/web", Message: "Style header", SHA: "w1"},
+		},
+		"octocat/dotfiles": {
+			{Repository: "octocat/dotfiles", Message: "Tweak vimrc", SHA: "d1"},
+		},
+	}
+}
+
+// largeCommits is a fixture of n repositories across a few owners, with
+// one to five commits each.
+func largeCommits(n int) map[string][]entity.Commit {
+	owners := []string{"acme", "octocat", "globex", "initech"}
+	commits := make(map[string][]entity.Commit, n)
+	for i := range n {
+		repo := fmt.Sprintf("%s/repo-%03d", owners[i%len(owners)], i)
+		for j := range i%5 + 1 {
+			commits[repo] = append(commits[repo], entity.Commit{
+				Repository: repo,
+				Message:    fmt.Sprintf("Change %d in %s", j, repo),
+				SHA:        fmt.Sprintf("%03d%d", i, j),
+			})
+		}
+	}
+	return commits
+}
@@ -2,11 +2,22 @@ package ui
 
 import (
 	"fmt"
+	"math"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/DementevVV/commitsum/internal/domain/entity"
 )
 
+// formatDateRange formats a date range for the terminal, with the range
+// separator of the current symbol set. Exports use entity.FormatDateDisplay.
+func formatDateRange(startDate, endDate string) string {
+	return entity.FormatDateRange(startDate, endDate, iconRange)
+}
+
 // renderHeader renders a header with app name and screen title.
 func renderHeader(screenTitle string) string {
 	app := styleHeaderApp.Render("commitsum")
@@ -15,16 +26,66 @@ func renderHeader(screenTitle string) string {
 	return app + divider + title + "\n\n"
 }
 
+// helpDisabled marks a help bar item whose action is currently unavailable.
+const helpDisabled = "disabled"
+
+// helpItem returns a help bar item, dimmed when the action is unavailable in
+// the current state.
+func helpItem(key, text string, enabled bool) []string {
+	if enabled {
+		return []string{key, text}
+	}
+	return []string{key, text, helpDisabled}
+}
+
 // renderHelpBar renders a help bar with key-value pairs and top border.
-func renderHelpBar(items [][]string) string {
-	var parts []string
+// Items built with helpItem(..., false) are dimmed. It renders nothing
+// while the help bar is hidden.
+func (m *Model) renderHelpBar(items [][]string) string {
+	if m.hideHelpBar {
+		return ""
+	}
+	return styleHelpBar.Render(wrapHelpItems(items, m.contentWidth()))
+}
+
+// renderBox wraps screen content in the main box. Trailing newlines are
+// dropped so a hidden help bar leaves no blank line at the bottom.
+func renderBox(content string) string {
+	return "\n" + styleBox.Render(strings.TrimRight(content, "\n")) + "\n"
+}
+
+// wrapHelpItems joins key hints with dividers, starting a new line whenever
+// the next "key text" pair would exceed width. Pairs are never split; a
+// width of zero or less keeps everything on one line.
+func wrapHelpItems(items [][]string, width int) string {
+	divider := styleHelpDivider.String()
+	dividerWidth := lipgloss.Width(divider)
+
+	var lines []string
+	var line string
+	lineWidth := 0
 	for _, item := range items {
-		key := styleHelpKey.Render(item[0])
-		text := styleHelpText.Render(item[1])
-		parts = append(parts, key+" "+text)
+		part := styleHelpKey.Render(item[0]) + " " + styleHelpText.Render(item[1])
+		if len(item) > 2 && item[2] == helpDisabled {
+			part = styleHelpDisabled.Render(item[0] + " " + item[1])
+		}
+		partWidth := lipgloss.Width(part)
+
+		switch {
+		case line == "":
+			line, lineWidth = part, partWidth
+		case width > 0 && lineWidth+dividerWidth+partWidth > width:
+			lines = append(lines, line)
+			line, lineWidth = part, partWidth
+		default:
+			line += divider + part
+			lineWidth += dividerWidth + partWidth
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
 	}
-	content := strings.Join(parts, styleHelpDivider.String())
-	return styleHelpBar.Render(content)
+	return strings.Join(lines, "\n")
 }
 
 // renderDivider renders a horizontal divider line.
@@ -55,24 +116,173 @@ func renderListHeader(label string, count int) string {
 	return styleListHeader.Render(fmt.Sprintf("%s (%d)", label, count))
 }
 
-// renderProgressBar creates a beautiful gradient progress bar.
-func renderProgressBar(value, maxValue, width int) string {
-	if maxValue == 0 {
-		maxValue = 1
+// clampIndex limits i to [0, n-1], returning 0 when n is zero.
+func clampIndex(i, n int) int {
+	return max(0, min(i, n-1))
+}
+
+// nextRepoSort returns the sort order following the current one.
+func nextRepoSort(current entity.RepoSortOrder) entity.RepoSortOrder {
+	for i, order := range entity.RepoSortOrders {
+		if order == current {
+			return entity.RepoSortOrders[(i+1)%len(entity.RepoSortOrders)]
+		}
 	}
-	if width <= 0 {
-		width = 20
+	return entity.RepoSortOrders[0]
+}
+
+// maskRepoName hides the repository name, keeping the owner. The mask has a
+// fixed length so it does not leak the name's length.
+func maskRepoName(repo string) string {
+	owner, _ := entity.SplitRepo(repo)
+	if owner == "" {
+		return iconMask
 	}
+	return owner + "/" + iconMask
+}
 
-	// Calculate filled width proportionally.
-	filledWidth := (value * width) / maxValue
-	if filledWidth > width {
-		filledWidth = width
+// favoritesTag returns an inline indicator when the favorites filter is on.
+func (m *Model) favoritesTag() string {
+	if !m.favoritesOnly {
+		return ""
 	}
-	if value > 0 && filledWidth == 0 {
-		filledWidth = 1 // At least 1 block if there's any value.
+	return styleTag.Render("  " + iconSeparator + " " + iconFavorite + " favorites only")
+}
+
+// privacyTag returns an inline indicator when privacy mode is active.
+func (m *Model) privacyTag() string {
+	if !m.privacyMode {
+		return ""
+	}
+	return styleTag.Render("  " + iconSeparator + " privacy mode")
+}
+
+// rateLimitLine renders the known API quota, with a warning when the next
+// fetch is likely to exceed it.
+func (m *Model) rateLimitLine() string {
+	if m.rateLimit == nil {
+		return ""
+	}
+	s := "\n" + styleTag.Render(m.rateLimit.String()) + "\n"
+	if m.rateLimitWarning != "" {
+		s += renderWarningBanner(m.rateLimitWarning) + "\n"
+	}
+	return s
+}
+
+// commitCounts returns the number of commits per repository.
+func commitCounts(commits map[string][]entity.Commit) map[string]int {
+	counts := make(map[string]int, len(commits))
+	for repo, repoCommits := range commits {
+		counts[repo] = len(repoCommits)
+	}
+	return counts
+}
+
+// countGains returns, per repository, how many commits were added since the
+// previous counts. Repositories that did not grow are omitted.
+func countGains(prev, current map[string]int) map[string]int {
+	gains := make(map[string]int)
+	for repo, count := range current {
+		if diff := count - prev[repo]; diff > 0 {
+			gains[repo] = diff
+		}
+	}
+	return gains
+}
+
+// hiddenCounts returns how many commits and repos the active filters hide.
+func (m *Model) hiddenCounts() (commits, repos int) {
+	return m.commitUC.CountHidden(m.commits, m.repoList, m.visibleRepos(), m.droppedCommits)
+}
+
+// dataAgeTag returns an inline indicator of how old the loaded data is.
+func (m *Model) dataAgeTag() string {
+	if m.fetchedAt.IsZero() {
+		return ""
+	}
+	return styleTag.Render("  " + iconSeparator + " " + formatAge(time.Since(m.fetchedAt)))
+}
+
+// formatAge describes a data age as "just now" or "cached 3m ago".
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("cached %dm ago", int(age.Minutes()))
+	default:
+		return fmt.Sprintf("cached %dh ago", int(age.Hours()))
+	}
+}
+
+// isNewCommit reports whether a commit was not yet seen for the loaded range.
+func (m *Model) isNewCommit(commit entity.Commit) bool {
+	return m.seen != nil && commit.SHA != "" && !m.seen[commit.SHA]
+}
+
+// isASCII reports whether s contains only ASCII characters.
+func isASCII(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+// renderCommitLine renders a single commit with its bullet and tags.
+func renderCommitLine(commit entity.Commit, bullet string, mc entity.MessageCase, showSHA, isNew bool) string {
+	line := styleHighlight.Render(bullet) + " "
+	if sha := commit.ShortSHA(); showSHA && sha != "" {
+		line += styleTag.Render(sha) + " "
+	}
+	line += styleCommit.Render(mc.Apply(commit.Message))
+	if commit.Coauthored {
+		line += styleTag.Render(" (co-author)")
+	}
+	if commit.Local {
+		line += styleTag.Render(" (local)")
+	}
+	if commit.IsRevert() {
+		line += styleTag.Render(" (revert)")
+	}
+	if isNew {
+		line += styleGainBadge.Render(" new")
+	}
+	return line
+}
+
+// barLength returns how many of width cells a bar for value fills relative
+// to maxValue. On a log scale lengths follow log(1+value), so small values
+// stay visible next to a dominant one.
+func barLength(value, maxValue, width int, logScale bool) int {
+	if maxValue <= 0 {
+		maxValue = 1
+	}
+
+	var filled int
+	if logScale {
+		filled = int(math.Round(math.Log1p(float64(value)) / math.Log1p(float64(maxValue)) * float64(width)))
+	} else {
+		filled = (value * width) / maxValue
+	}
+	if filled > width {
+		filled = width
+	}
+	if value > 0 && filled == 0 {
+		filled = 1 // At least 1 block if there's any value.
+	}
+	return filled
+}
+
+// renderProgressBar creates a beautiful gradient progress bar.
+func renderProgressBar(value, maxValue, width int, logScale bool) string {
+	if width <= 0 {
+		width = 20
 	}
 
+	filledWidth := barLength(value, maxValue, width, logScale)
 	emptyWidth := width - filledWidth
 
 	// Create gradient effect across the filled portion.
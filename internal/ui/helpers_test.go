@@ -0,0 +1,278 @@
+package ui
+
+import (
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/DementevVV/commitsum/internal/domain/entity"
+)
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{age: 0, want: "just now"},
+		{age: 59 * time.Second, want: "just now"},
+		{age: time.Minute, want: "cached 1m ago"},
+		{age: 3*time.Minute + 30*time.Second, want: "cached 3m ago"},
+		{age: 59 * time.Minute, want: "cached 59m ago"},
+		{age: 2*time.Hour + 10*time.Minute, want: "cached 2h ago"},
+	}
+
+	for _, tt := range tests {
+		if got := formatAge(tt.age); got != tt.want {
+			t.Errorf("formatAge(%v) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestRepoListShowsDataAge(t *testing.T) {
+	tests := []struct {
+		name string
+		age  time.Duration
+		want string
+	}{
+		{name: "live", age: 0, want: "just now"},
+		{name: "cached", age: 3 * time.Minute, want: "cached 3m ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, nil)
+			h.load(sampleCommits())
+			h.m.fetchedAt = time.Now().Add(-tt.age)
+
+			if view := h.m.View(); !strings.Contains(view, tt.want) {
+				t.Errorf("repo list header lacks %q:\n%s", tt.want, view)
+			}
+		})
+	}
+}
+
+func TestWrapHelpItems(t *testing.T) {
+	items := [][]string{
+		{"enter", "confirm"},
+		{"space", "toggle"},
+		{"a", "select all"},
+		{"f", "filter"},
+		{"q", "quit"},
+	}
+	pairs := []string{"enter confirm", "space toggle", "a select all", "f filter", "q quit"}
+
+	tests := []struct {
+		name      string
+		width     int
+		wantLines int
+	}{
+		{name: "unknown width", width: 0, wantLines: 1},
+		{name: "wide", width: 200, wantLines: 1},
+		{name: "narrow", width: 30, wantLines: 3},
+		{name: "narrower than a pair", width: 5, wantLines: len(items)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := strings.Split(wrapHelpItems(items, tt.width), "\n")
+			if len(lines) != tt.wantLines {
+				t.Errorf("got %d lines, want %d:\n%s", len(lines), tt.wantLines, strings.Join(lines, "\n"))
+			}
+
+			// Every pair sits whole on exactly one line, in order.
+			joined := strings.Join(lines, "\n")
+			last := -1
+			for _, pair := range pairs {
+				i := strings.Index(joined, pair)
+				if i < 0 {
+					t.Errorf("pair %q split or missing:\n%s", pair, joined)
+					continue
+				}
+				if i < last {
+					t.Errorf("pair %q out of order", pair)
+				}
+				last = i
+			}
+
+			for _, line := range lines {
+				// A single pair may exceed a tiny width; it is never split.
+				if w := lipgloss.Width(line); tt.width > 0 && w > tt.width && strings.Contains(line, styleHelpDivider.String()) {
+					t.Errorf("line %q is %d wide, want at most %d", line, w, tt.width)
+				}
+			}
+		})
+	}
+}
+
+func TestHelpBarFollowsWindowWidth(t *testing.T) {
+	h := newHarness(t, nil)
+	h.load(sampleCommits())
+
+	h.send(tea.WindowSizeMsg{Width: 200, Height: 40})
+	wide := strings.Count(h.m.View(), "\n")
+	h.send(tea.WindowSizeMsg{Width: 40, Height: 40})
+	narrow := strings.Count(h.m.View(), "\n")
+
+	if narrow <= wide {
+		t.Errorf("narrow view has %d lines, wide %d; want the help bar to wrap", narrow, wide)
+	}
+}
+
+func TestCountGains(t *testing.T) {
+	tests := []struct {
+		name    string
+		prev    map[string]int
+		current map[string]int
+		want    map[string]int
+	}{
+		{name: "no change", prev: map[string]int{"acme/api": 2}, current: map[string]int{"acme/api": 2}, want: map[string]int{}},
+		{name: "grown", prev: map[string]int{"acme/api": 2}, current: map[string]int{"acme/api": 5}, want: map[string]int{"acme/api": 3}},
+		{name: "shrunk", prev: map[string]int{"acme/api": 2}, current: map[string]int{"acme/api": 1}, want: map[string]int{}},
+		{name: "new repo", prev: map[string]int{"acme/api": 2}, current: map[string]int{"acme/api": 2, "acme/web": 1}, want: map[string]int{"acme/web": 1}},
+		{name: "repo gone", prev: map[string]int{"acme/api": 2, "acme/web": 1}, current: map[string]int{"acme/api": 2}, want: map[string]int{}},
+		{name: "no previous counts", current: map[string]int{"acme/api": 2}, want: map[string]int{"acme/api": 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countGains(tt.prev, tt.current); !maps.Equal(got, tt.want) {
+				t.Errorf("countGains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrackCountGains(t *testing.T) {
+	h := newHarness(t, sampleCommits())
+	h.m.startDate, h.m.endDate = "2026-02-02", "2026-02-02"
+
+	h.load(sampleCommits())
+	if len(h.m.gained) != 0 {
+		t.Fatalf("gained = %v after the first load, want none", h.m.gained)
+	}
+
+	more := sampleCommits()
+	more["acme/web"] = append(more["acme/web"], entity.Commit{Repository: "acme/web", Message: "Fix footer", SHA: "w2"})
+	h.load(more)
+	if want := map[string]int{"acme/web": 1}; !maps.Equal(h.m.gained, want) {
+		t.Fatalf("gained = %v after a refresh, want %v", h.m.gained, want)
+	}
+	if view := h.m.View(); !strings.Contains(view, "+1") {
+		t.Errorf("repo list does not show the +1 badge:\n%s", view)
+	}
+
+	h.m.startDate = "2026-02-01"
+	h.load(more)
+	if len(h.m.gained) != 0 {
+		t.Errorf("gained = %v after changing the range, want none", h.m.gained)
+	}
+
+	h.m.startDate = "2026-02-02"
+	h.load(sampleCommits())
+	h.load(more)
+	h.press("r")
+	if h.m.gained != nil {
+		t.Errorf("gained = %v after refresh navigation, want cleared", h.m.gained)
+	}
+}
+
+func TestCountGainsBaseline(t *testing.T) {
+	more := sampleCommits()
+	more["acme/web"] = append(more["acme/web"], entity.Commit{Repository: "acme/web", Message: "Fix footer", SHA: "w2"})
+
+	t.Run("failed fetch keeps the baseline", func(t *testing.T) {
+		h := newHarness(t, sampleCommits())
+		h.m.startDate, h.m.endDate = "2026-02-02", "2026-02-02"
+		h.load(sampleCommits())
+
+		h.m.screen = screenLoading
+		h.send(commitsLoadedMsg{err: errors.New("network down")})
+		h.load(more)
+		if want := map[string]int{"acme/web": 1}; !maps.Equal(h.m.gained, want) {
+			t.Errorf("gained = %v after a failed fetch, want %v", h.m.gained, want)
+		}
+	})
+
+	t.Run("date field toggle starts a new baseline", func(t *testing.T) {
+		h := newHarness(t, more)
+		h.m.startDate, h.m.endDate = "2026-02-02", "2026-02-02"
+		h.load(sampleCommits())
+
+		h.press("t")
+		h.wantScreen(screenRepoList)
+		if len(h.m.gained) != 0 {
+			t.Errorf("gained = %v after toggling the date field, want none", h.m.gained)
+		}
+	})
+}
+
+func TestRenderCommitLineSHA(t *testing.T) {
+	tests := []struct {
+		name    string
+		sha     string
+		showSHA bool
+		want    string
+	}{
+		{name: "off", sha: "abc1234def", want: iconCommit + " Add login"},
+		{name: "short SHA", sha: "abc1234def", showSHA: true, want: iconCommit + " abc1234 Add login"},
+		{name: "no SHA", showSHA: true, want: iconCommit + " Add login"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commit := entity.Commit{Repository: "acme/api", Message: "Add login", SHA: tt.sha}
+			if got := renderCommitLine(commit, iconCommit, entity.MessageCaseNone, tt.showSHA, false); got != tt.want {
+				t.Errorf("renderCommitLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBarLength(t *testing.T) {
+	tests := []struct {
+		value, max int
+		log        bool
+		want       int
+	}{
+		{value: 0, max: 80, log: true, want: 0},
+		{value: 1, max: 80, log: true, want: 4},
+		{value: 5, max: 80, log: true, want: 10},
+		{value: 20, max: 80, log: true, want: 17},
+		{value: 80, max: 80, log: true, want: 25},
+		{value: 100, max: 80, log: true, want: 25},
+		{value: 0, max: 0, log: true, want: 0},
+		{value: 1, max: 80, want: 1},
+		{value: 5, max: 80, want: 1},
+		{value: 20, max: 80, want: 6},
+		{value: 80, max: 80, want: 25},
+	}
+
+	for _, tt := range tests {
+		if got := barLength(tt.value, tt.max, 25, tt.log); got != tt.want {
+			t.Errorf("barLength(%d, %d, 25, log=%v) = %d, want %d", tt.value, tt.max, tt.log, got, tt.want)
+		}
+	}
+}
+
+func TestClampIndex(t *testing.T) {
+	tests := []struct {
+		i, n, want int
+	}{
+		{0, 3, 0},
+		{2, 3, 2},
+		{5, 3, 2},
+		{-1, 3, 0},
+		{4, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := clampIndex(tt.i, tt.n); got != tt.want {
+			t.Errorf("clampIndex(%d, %d) = %d, want %d", tt.i, tt.n, got, tt.want)
+		}
+	}
+}
@@ -1,16 +1,24 @@
 package ui
 
 import (
+	"errors"
+	"fmt"
+	"maps"
+	"os/exec"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 
 	"github.com/DementevVV/commitsum/internal/domain/entity"
 	"github.com/DementevVV/commitsum/internal/domain/repository"
 	"github.com/DementevVV/commitsum/internal/infrastructure/config"
+	"github.com/DementevVV/commitsum/internal/infrastructure/logger"
 	"github.com/DementevVV/commitsum/internal/usecase"
 )
 
@@ -26,6 +34,21 @@ const (
 	screenExport
 	screenStats
 	screenLoading
+	screenCompareInput
+	screenCompare
+	screenAbout
+	screenWizard
+	screenConfirmRange
+	screenCompareRangeInput
+	screenProfile
+)
+
+// Wizard steps.
+const (
+	wizardStepDateRange = iota
+	wizardStepOutputFormat
+	wizardStepAutoCopy
+	wizardStepCount
 )
 
 // Model represents the application state for the TUI.
@@ -38,6 +61,7 @@ type Model struct {
 	// Selection state.
 	cursor   int
 	selected map[string]bool
+	repoSort entity.RepoSortOrder
 
 	// Screen state.
 	screen screenState
@@ -47,15 +71,36 @@ type Model struct {
 	filterInput  textinput.Model
 	spinner      spinner.Model
 	filterActive bool
+	// dateLimitHit is set when typed or pasted input overflowed dateInputLimit.
+	dateLimitHit bool
+	// dateInputSelected is set while the pre-filled date is selected: the
+	// first typed character replaces it and backspace clears it.
+	dateInputSelected bool
 
 	// Date range.
 	dateRangeIdx int
 	startDate    string
 	endDate      string
+	// rangePreset is the key of the preset the current range came from.
+	rangePreset string
+	rangeLabel  string
+	// initialRange is set when the range was given on the command line.
+	initialRange bool
 
 	// Export.
-	exportFormat  int
-	exportFormats []string
+	exportFormat       int
+	exportFormats      []string
+	exportFilteredOnly bool
+
+	// Teammate and range comparison.
+	teammateInput     textinput.Model
+	compareRangeInput textinput.Model
+	comparison        *entity.Comparison
+	// compareFrom is the screen the comparison returns to.
+	compareFrom screenState
+
+	// Selection profiles.
+	profileInput textinput.Model
 
 	// Config & Stats.
 	config config.Config
@@ -66,30 +111,166 @@ type Model struct {
 	exportUC  *usecase.ExportUseCase
 	clipboard repository.ClipboardRepository
 
+	// Build information shown on the about screen.
+	version   string
+	buildTime string
+	// First-run wizard state.
+	wizardStep   int
+	wizardCursor int
+
+	// confirmClearCache is set while asking whether to clear the cache.
+	confirmClearCache bool
+	// refreshRanges are the presets being refetched into the cache, one at a
+	// time; refreshErrs holds the outcome of each finished one.
+	refreshRanges []refreshRange
+	refreshErrs   []error
+
+	// exportOptions are the presentation toggles passed to exporters.
+	exportOptions entity.ExportOptions
+	// bullet is the commit line marker in views (bullet_style or iconCommit).
+	bullet string
+
+	// minCommits omits selected repos with fewer commits from the summary
+	// and exports; starts at summary_min_commits.
+	minCommits int
+
+	// confirmedRange is the last large range the user agreed to load, so
+	// refreshing it does not ask again.
+	confirmedRange string
+
+	// aboutReturn is the screen to go back to when the about screen closes.
+	aboutReturn screenState
+
+	// width is the terminal width from the last WindowSizeMsg (0 until known).
+	width int
+
+	// expandAll temporarily lifts the max_expanded_repos cap.
+	expandAll bool
+
+	// showPercent adds each repo's share of the displayed commits to the list.
+	showPercent bool
+
+	// privacyMode masks repository names in views (exports keep full names).
+	privacyMode bool
+	// favoritesOnly limits the repo list to favorite_repos (toggled with F).
+	favoritesOnly bool
+	// capped is set when the loaded range hit GitHub's result cap.
+	capped bool
+	// hideMerges leaves merge commits out of the summary and exports.
+	hideMerges bool
+	// seen holds the commit SHAs already viewed for the loaded range; nil
+	// when nothing was recorded, so no commit is marked new.
+	seen map[string]bool
+	// hideHelpBar suppresses the key hints at the bottom of every screen.
+	hideHelpBar bool
+	// loginCommand builds the interactive GitHub login offered on
+	// authentication errors; nil hides the action.
+	loginCommand func() *exec.Cmd
+
+	// placeholderRepos are repo names shown as a skeleton while loading.
+	placeholderRepos []string
+
 	// Status.
 	err     error
 	message string
-	warning string
-	loading bool
+	// messageID identifies the current message so only its own expiry clears it.
+	messageID int
+	warning   string
+	// exportWarning reports a failed post-process command on export screens.
+	exportWarning string
+	loading       bool
+	// fetchedAt is when the loaded commits were fetched from GitHub.
+	fetchedAt time.Time
+	// Per-repo commit counts of the previous fetch of loadedRange, and the
+	// increases found when the same range was fetched again.
+	loadedRange string
+	prevCounts  map[string]int
+	gained      map[string]int
+	// droppedCommits counts commits removed while fetching (branch filter, empty messages).
+	droppedCommits int
+
+	// exitMessage is printed to stderr once the program has quit, for
+	// messages set on the way out that the TUI no longer gets to render.
+	exitMessage string
+
+	// Session counters for the optional usage summary on exit.
+	fetchCount  int
+	exportCount int
+	copyCount   int
+
+	// rateLimit is the last known search API quota (nil until checked).
+	rateLimit        *entity.RateLimit
+	rateLimitWarning string
 }
 
 // commitsLoadedMsg is sent when commits finish loading.
 type commitsLoadedMsg struct {
-	commits  map[string][]entity.Commit
-	repoList []string
-	warning  string
-	err      error
+	commits   map[string][]entity.Commit
+	repoList  []string
+	warning   string
+	fetchedAt time.Time
+	dropped   int
+	seen      map[string]bool
+	capped    bool
+	err       error
+}
+
+// rateLimitLoadedMsg is sent when the API quota has been checked.
+type rateLimitLoadedMsg struct {
+	status  *entity.RateLimit
+	warning string
+	err     error
 }
 
+// messageExpiredMsg is sent when a status message's display time is up.
+type messageExpiredMsg struct {
+	id int
+}
+
+// clipboardResultMsg is sent when a clipboard copy finishes. success is the
+// banner shown when it worked; quit exits afterwards (only on success).
+type clipboardResultMsg struct {
+	success string
+	quit    bool
+	err     error
+}
+
+// authLoginDoneMsg is sent when gh auth login exits and the TUI resumes.
+type authLoginDoneMsg struct {
+	err error
+}
+
+// refreshRange is a preset range refetched by the cache refresh action.
+type refreshRange struct {
+	label string
+	entity.DateRange
+}
+
+// rangeRefreshedMsg is sent when one range of a cache refresh finishes.
+type rangeRefreshedMsg struct {
+	err error
+}
+
+// comparisonLoadedMsg is sent when a teammate or range comparison finishes
+// loading. input is the screen to return to on error.
+type comparisonLoadedMsg struct {
+	comparison *entity.Comparison
+	input      screenState
+	err        error
+}
+
+// dateInputLimit is the length of a YYYY-MM-DD date.
+const dateInputLimit = 10
+
 // NewModel creates and initializes a new UI model.
 func NewModel(cfg config.Config, commitUC *usecase.CommitUseCase, exportUC *usecase.ExportUseCase, clipboard repository.ClipboardRepository) *Model {
-	today := time.Now().Format("2006-01-02")
+	today := entity.Today()
 
 	// Initialize date text input.
 	ti := textinput.New()
 	ti.Placeholder = "YYYY-MM-DD"
 	ti.Focus()
-	ti.CharLimit = 10
+	ti.CharLimit = dateInputLimit
 	ti.Width = 20
 	ti.SetValue(today)
 	ti.Prompt = ""
@@ -112,51 +293,535 @@ func NewModel(cfg config.Config, commitUC *usecase.CommitUseCase, exportUC *usec
 		fi.SetValue(cfg.RepoFilter)
 	}
 
+	// Initialize teammate text input.
+	tmi := textinput.New()
+	tmi.Placeholder = "GitHub username"
+	tmi.CharLimit = 39
+	tmi.Width = 30
+	tmi.Prompt = ""
+	tmi.PromptStyle = lipgloss.NewStyle().Foreground(colorPrimaryLight)
+	tmi.TextStyle = lipgloss.NewStyle().Foreground(colorPrimary)
+	tmi.PlaceholderStyle = lipgloss.NewStyle().Foreground(colorTextMuted)
+	tmi.Cursor.Style = lipgloss.NewStyle().Foreground(colorAccent)
+	if cfg.Teammate != "" {
+		tmi.SetValue(cfg.Teammate)
+	}
+
+	cri := textinput.New()
+	cri.Placeholder = "last week..yesterday"
+	cri.CharLimit = 64
+	cri.Width = 30
+	cri.Prompt = ""
+	cri.PromptStyle = lipgloss.NewStyle().Foreground(colorPrimaryLight)
+	cri.TextStyle = lipgloss.NewStyle().Foreground(colorPrimary)
+	cri.PlaceholderStyle = lipgloss.NewStyle().Foreground(colorTextMuted)
+	cri.Cursor.Style = lipgloss.NewStyle().Foreground(colorAccent)
+
+	pi := textinput.New()
+	pi.Placeholder = "standup"
+	pi.CharLimit = 40
+	pi.Width = 30
+	pi.Prompt = ""
+	pi.PromptStyle = lipgloss.NewStyle().Foreground(colorPrimaryLight)
+	pi.TextStyle = lipgloss.NewStyle().Foreground(colorPrimary)
+	pi.PlaceholderStyle = lipgloss.NewStyle().Foreground(colorTextMuted)
+	pi.Cursor.Style = lipgloss.NewStyle().Foreground(colorAccent)
+
+	// Plain terminals (dumb, CI, piped output) get ASCII symbols.
+	plain := lipgloss.ColorProfile() == termenv.Ascii
+	if plain {
+		useASCII()
+	}
+	// Non-ASCII bullets keep the "-" fallback on plain terminals, in the
+	// views and in exports rendered from them.
+	bullet := iconCommit
+	if cfg.BulletStyle != "" && (!plain || isASCII(cfg.BulletStyle)) {
+		bullet = cfg.BulletStyle
+	}
+	exportOptions := cfg.ExportOptions()
+	if plain && !isASCII(exportOptions.Bullet) {
+		exportOptions.Bullet = ""
+	}
+
 	// Initialize spinner.
 	sp := spinner.New()
 	sp.Spinner = spinner.Dot
+	if plain {
+		sp.Spinner = spinner.Line
+	}
 	sp.Style = lipgloss.NewStyle().Foreground(colorPrimary)
 
 	return &Model{
-		dateInput:     ti,
-		filterInput:   fi,
-		spinner:       sp,
-		screen:        screenDateRange,
-		selected:      make(map[string]bool),
-		config:        cfg,
-		exportFormats: []string{"text", "markdown", "json"},
-		startDate:     today,
-		endDate:       today,
-		commitUC:      commitUC,
-		exportUC:      exportUC,
-		clipboard:     clipboard,
+		dateInput:         ti,
+		filterInput:       fi,
+		teammateInput:     tmi,
+		compareRangeInput: cri,
+		profileInput:      pi,
+		spinner:           sp,
+		screen:            screenDateRange,
+		selected:          make(map[string]bool),
+		dateRangeIdx:      defaultPresetIndex(cfg.DefaultDateRange),
+		repoSort:          entity.RepoSortOrder(cfg.RepoSort),
+		minCommits:        cfg.SummaryMinCommits,
+		hideHelpBar:       cfg.HideHelpBar,
+		exportOptions:     exportOptions,
+		bullet:            bullet,
+		config:            cfg,
+		exportFormats:     []string{"text", "markdown", "json", "heatmap", "pr-body", "github-tasks", "daily", "compact", "legacy-json"},
+		startDate:         today,
+		endDate:           today,
+		commitUC:          commitUC,
+		exportUC:          exportUC,
+		clipboard:         clipboard,
 	}
 }
 
 // Init implements the Bubble Tea model interface.
 func (m *Model) Init() tea.Cmd {
-	return textinput.Blink
+	if m.initialRange {
+		_, load := m.loadCommits()
+		return tea.Batch(load, m.loadRateLimit())
+	}
+	return tea.Batch(textinput.Blink, m.loadRateLimit())
+}
+
+// StartWizard opens the first-run setup wizard instead of date selection.
+func (m *Model) StartWizard() {
+	m.screen = screenWizard
+	m.wizardStep = wizardStepDateRange
+	m.wizardCursor = indexOf(m.wizardOptions(), m.config.DefaultDateRange)
+}
+
+// wizardOptions returns the config values offered by the current wizard step.
+func (m *Model) wizardOptions() []string {
+	switch m.wizardStep {
+	case wizardStepDateRange:
+		var keys []string
+		for _, preset := range entity.DateRangePresets {
+			if preset.Key != "custom" {
+				keys = append(keys, preset.Key)
+			}
+		}
+		return keys
+	case wizardStepOutputFormat:
+		return []string{"text", "markdown", "json"}
+	default:
+		return []string{"no", "yes"}
+	}
+}
+
+// SetBuildInfo sets the version and build time shown on the about screen.
+func (m *Model) SetBuildInfo(version, buildTime string) {
+	m.version = version
+	m.buildTime = buildTime
+}
+
+// SetLoginCommand sets how the interactive GitHub login is started from the
+// authentication error screen.
+func (m *Model) SetLoginCommand(login func() *exec.Cmd) {
+	m.loginCommand = login
+}
+
+// ExitMessage returns the message to print after the program quits, or "".
+func (m *Model) ExitMessage() string {
+	return m.exitMessage
+}
+
+// SessionSummary describes the session's activity in one line, e.g.
+// "Fetched 2 ranges, exported 1 file, copied 3 times". Nothing leaves the
+// machine; it is only printed or logged locally.
+func (m *Model) SessionSummary() string {
+	return fmt.Sprintf("Fetched %d %s, exported %d %s, copied %d %s",
+		m.fetchCount, pluralize(m.fetchCount, "range", "ranges"),
+		m.exportCount, pluralize(m.exportCount, "file", "files"),
+		m.copyCount, pluralize(m.copyCount, "time", "times"))
+}
+
+// pluralize picks the singular or plural form for n.
+func pluralize(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// SetInitialRange skips date selection and loads the given range on start.
+func (m *Model) SetInitialRange(startDate, endDate string) {
+	m.startDate = startDate
+	m.endDate = endDate
+	m.rangePreset = "custom"
+	m.initialRange = true
 }
 
 // getDisplayRepos returns the repos to display based on filter state.
 func (m *Model) getDisplayRepos() []string {
+	sorted := usecase.SortRepos(m.visibleRepos(), m.commits, m.repoSort)
+
+	// Pin favorites to the top, keeping the sort order within each part.
+	display := make([]string, 0, len(sorted))
+	for _, repo := range sorted {
+		if m.isFavorite(repo) {
+			display = append(display, repo)
+		}
+	}
+	for _, repo := range sorted {
+		if !m.isFavorite(repo) {
+			display = append(display, repo)
+		}
+	}
+	return display
+}
+
+// visibleRepos returns the fetched repos left by the pattern filter and,
+// when active, the favorites-only filter, in fetch order.
+func (m *Model) visibleRepos() []string {
+	repos := m.repoList
 	if m.filterActive {
-		return m.filteredRepos
+		repos = m.filteredRepos
+	}
+	if !m.favoritesOnly {
+		return repos
+	}
+	var favorites []string
+	for _, repo := range repos {
+		if m.isFavorite(repo) {
+			favorites = append(favorites, repo)
+		}
+	}
+	return favorites
+}
+
+// isFavorite reports whether repo is in favorite_repos.
+func (m *Model) isFavorite(repo string) bool {
+	return slices.Contains(m.config.FavoriteRepos, repo)
+}
+
+// toggleFavorite adds or removes repo from favorite_repos and saves the config.
+func (m *Model) toggleFavorite(repo string) {
+	if i := slices.Index(m.config.FavoriteRepos, repo); i >= 0 {
+		m.config.FavoriteRepos = slices.Delete(slices.Clone(m.config.FavoriteRepos), i, i+1)
+	} else {
+		m.config.FavoriteRepos = append(slices.Clone(m.config.FavoriteRepos), repo)
+	}
+	if err := config.Save(m.config); err != nil {
+		logger.Warn("Failed to save favorite_repos", "error", err)
+	}
+	logger.LogUserAction("toggle_favorite", "repo", repo, "favorite", m.isFavorite(repo))
+}
+
+// saveProfile stores the current selection as a named profile in
+// selection_profiles, replacing any profile with the same name.
+func (m *Model) saveProfile(name string) int {
+	var repos []string
+	for _, repo := range m.repoList {
+		if m.selected[repo] {
+			repos = append(repos, repo)
+		}
+	}
+	profiles := maps.Clone(m.config.SelectionProfiles)
+	if profiles == nil {
+		profiles = make(map[string][]string)
 	}
-	return m.repoList
+	profiles[name] = repos
+	m.config.SelectionProfiles = profiles
+	if err := config.Save(m.config); err != nil {
+		logger.Warn("Failed to save selection_profiles", "error", err)
+	}
+	logger.LogUserAction("save_profile", "profile", name, "repos", len(repos))
+	return len(repos)
+}
+
+// applyProfile replaces the selection with a profile's repositories that are
+// present in the loaded data. It returns the profile's repositories that had
+// no commits in the range, and false when the profile does not exist.
+func (m *Model) applyProfile(name string) (inactive []string, ok bool) {
+	repos, ok := m.config.SelectionProfiles[name]
+	if !ok {
+		return nil, false
+	}
+	m.selected = make(map[string]bool)
+	for _, repo := range repos {
+		if _, found := m.commits[repo]; found {
+			m.selected[repo] = true
+		} else {
+			inactive = append(inactive, repo)
+		}
+	}
+	logger.LogUserAction("apply_profile", "profile", name, "selected", len(m.selected), "inactive", len(inactive))
+	return inactive, true
+}
+
+// defaultPresetIndex returns the position of the configured default range.
+func defaultPresetIndex(key string) int {
+	for i, preset := range entity.DateRangePresets {
+		if preset.Key == key {
+			return i
+		}
+	}
+	return 0
+}
+
+// indexOf returns the index of value in values, or 0 when absent.
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return 0
+}
+
+// presetRange resolves a preset, leaving out today when that preference is on.
+func (m *Model) presetRange(preset string) entity.DateRange {
+	dr := entity.GetDateRange(preset)
+	if m.config.ExcludeToday {
+		dr = entity.ExcludeToday(dr)
+	}
+	return dr
+}
+
+// canExcludeToday reports whether the current range is a multi-day preset
+// that the exclude-today toggle applies to.
+func (m *Model) canExcludeToday() bool {
+	return m.rangePreset == "week" || m.rangePreset == "month"
+}
+
+// expandedRepos returns the selected repos whose commits are shown inline in
+// the repo list: the first max_expanded_repos in display order, plus the one
+// under the cursor. All selected repos expand when the cap is off.
+func (m *Model) expandedRepos(repos []string) map[string]bool {
+	limit := m.config.MaxExpandedRepos
+	expanded := make(map[string]bool)
+	for i, repo := range repos {
+		if !m.selected[repo] || m.isOwnerCollapsed(repo) {
+			continue
+		}
+		if m.expandAll || limit <= 0 || len(expanded) < limit || i == m.cursor {
+			expanded[repo] = true
+		}
+	}
+	return expanded
+}
+
+// canRecoverCap reports whether the loaded range hit the result cap and can
+// be narrowed, enabling the B (bisect) and D (last day) recovery keys.
+func (m *Model) canRecoverCap() bool {
+	return m.capped && m.startDate != m.endDate
+}
+
+// isOwnerCollapsed reports whether repo's owner group is collapsed.
+func (m *Model) isOwnerCollapsed(repo string) bool {
+	owner, _ := entity.SplitRepo(repo)
+	return owner != "" && slices.ContainsFunc(m.config.CollapsedOwners, func(o string) bool {
+		return strings.EqualFold(o, owner)
+	})
+}
+
+// toggleOwnerCollapsed collapses or expands the owner group of repo.
+func (m *Model) toggleOwnerCollapsed(repo string) {
+	owner, _ := entity.SplitRepo(repo)
+	if owner == "" {
+		return
+	}
+	owners := slices.DeleteFunc(slices.Clone(m.config.CollapsedOwners), func(o string) bool {
+		return strings.EqualFold(o, owner)
+	})
+	if len(owners) == len(m.config.CollapsedOwners) {
+		owners = append(owners, owner)
+	}
+	m.setCollapsedOwners(owners)
+}
+
+// collapseAllOwners collapses the owner group of every displayed repo.
+// Owners already collapsed but absent from this range stay collapsed.
+func (m *Model) collapseAllOwners() {
+	owners := slices.Clone(m.config.CollapsedOwners)
+	for _, repo := range m.getDisplayRepos() {
+		if !m.isOwnerCollapsed(repo) {
+			owner, _ := entity.SplitRepo(repo)
+			if owner != "" && !slices.Contains(owners, owner) {
+				owners = append(owners, owner)
+			}
+		}
+	}
+	m.setCollapsedOwners(owners)
+}
+
+// setCollapsedOwners stores the collapsed owners and saves the config so the
+// view is restored next session.
+func (m *Model) setCollapsedOwners(owners []string) {
+	m.config.CollapsedOwners = owners
+	if err := config.Save(m.config); err != nil {
+		logger.Warn("Failed to save collapsed_owners", "error", err)
+	}
+	logger.LogUserAction("collapse_owners", "owners", len(owners))
+}
+
+// isAuthError reports whether the current error needs a fresh GitHub login.
+func (m *Model) isAuthError() bool {
+	return m.err != nil && errors.Is(m.err, repository.ErrNotAuthenticated)
+}
+
+// canLogin reports whether the login action is offered for the current error.
+func (m *Model) canLogin() bool {
+	return m.isAuthError() && m.loginCommand != nil
+}
+
+// contentWidth returns the usable width inside the screen box, or 0 when the
+// terminal width is not yet known.
+func (m *Model) contentWidth() int {
+	if m.width == 0 {
+		return 0
+	}
+	return max(m.width-styleBox.GetHorizontalFrameSize(), 1)
+}
+
+// repoLabel returns the repository name as shown in views: its alias from
+// repo_aliases if any, masked in privacy mode.
+func (m *Model) repoLabel(repo string) string {
+	if m.privacyMode {
+		return maskRepoName(repo)
+	}
+	if alias := m.config.RepoAliases[repo]; alias != "" {
+		return alias
+	}
+	return repo
+}
+
+// repoLabels joins the display names of repos, honoring privacy mode and
+// aliases like repoLabel.
+func (m *Model) repoLabels(repos []string) string {
+	labels := make([]string, len(repos))
+	for i, repo := range repos {
+		labels[i] = m.repoLabel(repo)
+	}
+	return strings.Join(labels, ", ")
+}
+
+// isInputScreen reports whether the current screen has a focused text input.
+func (m *Model) isInputScreen() bool {
+	switch m.screen {
+	case screenDateSelect, screenRepoFilter, screenCompareInput, screenCompareRangeInput, screenProfile:
+		return true
+	}
+	return false
+}
+
+// summaryCommits returns the commits shown in the summary and exports,
+// without merge commits while they are hidden.
+func (m *Model) summaryCommits() map[string][]entity.Commit {
+	if !m.hideMerges {
+		return m.commits
+	}
+	return usecase.WithoutMerges(m.commits)
+}
+
+// clearFilter drops the repository filter and shows the full list again.
+func (m *Model) clearFilter() {
+	m.filterActive = false
+	m.filterInput.SetValue("")
+	m.filteredRepos = m.repoList
+}
+
+// inactiveRepos returns the watchlist repos without commits in the loaded
+// range.
+func (m *Model) inactiveRepos() []string {
+	return usecase.InactiveRepos(m.config.WatchlistRepos, m.commits)
+}
+
+// summarySelection returns the selected repos shown in the summary, leaving
+// out those with fewer than minCommits commits.
+func (m *Model) summarySelection() map[string]bool {
+	if m.minCommits <= 0 {
+		return m.selected
+	}
+
+	commits := m.summaryCommits()
+	selected := make(map[string]bool)
+	for repo, ok := range m.selected {
+		if ok && len(commits[repo]) >= m.minCommits {
+			selected[repo] = true
+		}
+	}
+	return selected
+}
+
+// summaryStats computes statistics over what the summary shows, so they are
+// the same wherever the stats screen is opened from.
+func (m *Model) summaryStats() *entity.Statistics {
+	return m.commitUC.CalculateStatistics(m.summaryCommits(), m.summarySelection())
+}
+
+// refreshing reports whether a cache refresh is still running.
+func (m *Model) refreshing() bool {
+	return len(m.refreshErrs) < len(m.refreshRanges)
 }
 
-// generateExportContent generates content for export.
+// clampCursor keeps the repository list cursor on a displayed repo, or at 0
+// when the list is empty.
+func (m *Model) clampCursor() {
+	m.cursor = clampIndex(m.cursor, len(m.getDisplayRepos()))
+}
+
+// hasSelection reports whether any repository with commits is selected.
+func (m *Model) hasSelection() bool {
+	for repo, ok := range m.selected {
+		if ok && len(m.commits[repo]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// belowMinCommits returns how many selected repos the summary leaves out.
+func (m *Model) belowMinCommits() int {
+	commits := m.summaryCommits()
+	return len(m.commitUC.GetSelectedReposSorted(commits, m.selected)) -
+		len(m.commitUC.GetSelectedReposSorted(commits, m.summarySelection()))
+}
+
+// exportSelection returns the selection used for exports: the summary
+// selection, restricted to the active filter when that option is on.
+func (m *Model) exportSelection() map[string]bool {
+	summary := m.summarySelection()
+	if !m.exportFilteredOnly || !m.filterActive {
+		return summary
+	}
+
+	selected := make(map[string]bool)
+	for _, repo := range m.filteredRepos {
+		if summary[repo] {
+			selected[repo] = true
+		}
+	}
+	return selected
+}
+
+// generateExportContent generates content for export, piped through the
+// configured post-process command. If that command fails, the unprocessed
+// content is returned and a warning is shown.
 func (m *Model) generateExportContent(format entity.ExportFormat) (string, error) {
-	dateStr := entity.FormatDateDisplay(m.startDate, m.endDate)
-	stats := m.commitUC.CalculateStatistics(m.commits, m.selected)
+	content, err := m.renderExportContent(format)
+	if err != nil {
+		return "", err
+	}
 
-	switch format {
-	case entity.FormatMarkdown:
-		return m.exportUC.ExportToMarkdown(m.commits, m.selected, dateStr, stats), nil
-	case entity.FormatJSON:
-		return m.exportUC.ExportToJSON(m.commits, m.selected, dateStr, stats)
-	default:
-		return m.exportUC.ExportToText(m.commits, m.selected, dateStr, stats), nil
+	processed, err := m.exportUC.PostProcess(content)
+	if err != nil {
+		logger.Warn("Post-process command failed", "error", err.Error())
+		m.exportWarning = "Post-process command failed, using unprocessed output: " + err.Error()
+		return content, nil
 	}
+	return processed, nil
+}
+
+// renderExportContent renders the selection in the given format.
+func (m *Model) renderExportContent(format entity.ExportFormat) (string, error) {
+	dateStr := entity.FormatDateDisplay(m.startDate, m.endDate)
+	selected := m.exportSelection()
+	commits := m.summaryCommits()
+	stats := m.commitUC.CalculateStatistics(commits, selected)
+	return m.exportUC.Render(format, commits, selected, dateStr, stats, func() entity.Heatmap {
+		return m.commitUC.BuildHeatmap(commits, selected)
+	}, m.exportOptions)
 }
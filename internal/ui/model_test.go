@@ -0,0 +1,394 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/DementevVV/commitsum/internal/domain/entity"
+	"github.com/DementevVV/commitsum/internal/infrastructure/config"
+)
+
+func TestExportSelection(t *testing.T) {
+	tests := []struct {
+		name         string
+		selected     []string
+		filter       []string
+		filteredOnly bool
+		want         []string
+	}{
+		{
+			name:     "no filter",
+			selected: []string{"acme/api", "octocat/dotfiles"},
+			want:     []string{"acme/api", "octocat/dotfiles"},
+		},
+		{
+			name:     "filter without the option",
+			selected: []string{"acme/api", "octocat/dotfiles"},
+			filter:   []string{"acme/api", "acme/web"},
+			want:     []string{"acme/api", "octocat/dotfiles"},
+		},
+		{
+			name:         "option without a filter",
+			selected:     []string{"acme/api", "octocat/dotfiles"},
+			filteredOnly: true,
+			want:         []string{"acme/api", "octocat/dotfiles"},
+		},
+		{
+			name:         "intersection",
+			selected:     []string{"acme/api", "octocat/dotfiles"},
+			filter:       []string{"acme/api", "acme/web"},
+			filteredOnly: true,
+			want:         []string{"acme/api"},
+		},
+		{
+			name:         "disjoint",
+			selected:     []string{"octocat/dotfiles"},
+			filter:       []string{"acme/api", "acme/web"},
+			filteredOnly: true,
+			want:         nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, nil)
+			h.load(sampleCommits())
+			for _, repo := range tt.selected {
+				h.m.selected[repo] = true
+			}
+			if tt.filter != nil {
+				h.m.filterActive = true
+				h.m.filteredRepos = tt.filter
+			}
+			h.m.exportFilteredOnly = tt.filteredOnly
+
+			var got []string
+			for repo, ok := range h.m.exportSelection() {
+				if ok {
+					got = append(got, repo)
+				}
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("exportSelection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandedRepos(t *testing.T) {
+	repos := []string{"a/1", "a/2", "a/3", "a/4", "a/5"}
+
+	tests := []struct {
+		name      string
+		selected  []string
+		limit     int
+		cursor    int
+		expandAll bool
+		want      []string
+	}{
+		{
+			name:     "first selected up to the cap",
+			selected: []string{"a/1", "a/3", "a/4", "a/5"},
+			limit:    2,
+			want:     []string{"a/1", "a/3"},
+		},
+		{
+			name:     "plus the one under the cursor",
+			selected: []string{"a/1", "a/3", "a/4", "a/5"},
+			limit:    2,
+			cursor:   4,
+			want:     []string{"a/1", "a/3", "a/5"},
+		},
+		{
+			name:     "cursor on an unselected repo",
+			selected: []string{"a/1", "a/3", "a/4"},
+			limit:    1,
+			cursor:   1,
+			want:     []string{"a/1"},
+		},
+		{
+			name:      "expand all lifts the cap",
+			selected:  []string{"a/1", "a/3", "a/4", "a/5"},
+			limit:     2,
+			expandAll: true,
+			want:      []string{"a/1", "a/3", "a/4", "a/5"},
+		},
+		{
+			name:     "cap off",
+			selected: []string{"a/1", "a/2", "a/3"},
+			limit:    0,
+			want:     []string{"a/1", "a/2", "a/3"},
+		},
+		{
+			name:  "nothing selected",
+			limit: 2,
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, nil)
+			h.m.config.MaxExpandedRepos = tt.limit
+			h.m.cursor = tt.cursor
+			h.m.expandAll = tt.expandAll
+			for _, repo := range tt.selected {
+				h.m.selected[repo] = true
+			}
+
+			var got []string
+			for repo := range h.m.expandedRepos(repos) {
+				got = append(got, repo)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expandedRepos() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummaryMinCommits(t *testing.T) {
+	// Repos with three, two and one commits.
+	commits := map[string][]entity.Commit{
+		"acme/api": {
+			{Repository: "acme/api", Message: "Add login", SHA: "a1"},
+			{Repository: "acme/api", Message: "Fix logout", SHA: "a2"},
+			{Repository: "acme/api", Message: "Bump deps", SHA: "a3"},
+		},
+		"acme/web": {
+			{Repository: "acme/web", Message: "Style header", SHA: "w1"},
+			{Repository: "acme/web", Message: "Fix footer", SHA: "w2"},
+		},
+		"octocat/dotfiles": {
+			{Repository: "octocat/dotfiles", Message: "Tweak vimrc", SHA: "d1"},
+		},
+	}
+
+	tests := []struct {
+		min         int
+		want        []string
+		wantOmitted int
+	}{
+		{min: 0, want: []string{"acme/api", "acme/web", "octocat/dotfiles"}},
+		{min: 1, want: []string{"acme/api", "acme/web", "octocat/dotfiles"}},
+		{min: 2, want: []string{"acme/api", "acme/web"}, wantOmitted: 1},
+		{min: 3, want: []string{"acme/api"}, wantOmitted: 2},
+		{min: 4, want: nil, wantOmitted: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("min %d", tt.min), func(t *testing.T) {
+			h := newHarness(t, nil, func(cfg *config.Config) { cfg.SummaryMinCommits = tt.min })
+			h.load(commits)
+			h.press("a")
+
+			var got []string
+			for repo, ok := range h.m.summarySelection() {
+				if ok {
+					got = append(got, repo)
+				}
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("summarySelection() = %v, want %v", got, tt.want)
+			}
+			if n := h.m.belowMinCommits(); n != tt.wantOmitted {
+				t.Errorf("belowMinCommits() = %d, want %d", n, tt.wantOmitted)
+			}
+
+			content, err := h.m.generateExportContent(entity.FormatText)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for repo := range commits {
+				if got := strings.Contains(content, repo); got != slices.Contains(tt.want, repo) {
+					t.Errorf("export includes %s = %v, want %v", repo, got, !got)
+				}
+			}
+
+			// The repo list keeps every repo regardless of the threshold.
+			if n := len(h.m.getDisplayRepos()); n != len(commits) {
+				t.Errorf("repo list shows %d repos, want %d", n, len(commits))
+			}
+		})
+	}
+}
+
+func TestSummaryMinCommitsKeys(t *testing.T) {
+	h := newHarness(t, nil)
+	h.load(sampleCommits())
+	h.press("a", "enter")
+	h.wantScreen(screenSummary)
+
+	h.press("+", "+")
+	if h.m.minCommits != 2 {
+		t.Fatalf("minCommits = %d after two +, want 2", h.m.minCommits)
+	}
+	if view := h.m.View(); !strings.Contains(view, "2 selected repos with fewer than 2 commits omitted") {
+		t.Errorf("summary does not explain the omitted repos:\n%s", view)
+	}
+
+	h.press("-", "-", "-")
+	if h.m.minCommits != 0 {
+		t.Errorf("minCommits = %d, want it to stop at 0", h.m.minCommits)
+	}
+}
+
+func TestStatsScopeMatchesSummary(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+	}{
+		{name: "from repo list", keys: []string{"s"}},
+		{name: "from summary", keys: []string{"enter", "s"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, nil)
+			h.load(sampleCommits())
+			h.press("a")
+			h.m.minCommits = 2
+
+			h.press(tt.keys...)
+			h.wantScreen(screenStats)
+			if h.m.stats.TotalRepositories != 1 || h.m.stats.TotalCommits != 2 {
+				t.Errorf("stats cover %d repos and %d commits, want the summary's 1 and 2",
+					h.m.stats.TotalRepositories, h.m.stats.TotalCommits)
+			}
+		})
+	}
+}
+
+func TestSummaryMergeToggle(t *testing.T) {
+	commits := map[string][]entity.Commit{
+		"acme/api": {
+			{Repository: "acme/api", Message: "Add login", SHA: "a1"},
+			{Repository: "acme/api", Message: "Merge pull request #42 from acme/login", SHA: "a2"},
+		},
+		"acme/web": {
+			{Repository: "acme/web", Message: "Merge branch 'main' into header", SHA: "w1"},
+		},
+	}
+	merges := []string{"Merge pull request #42", "Merge branch 'main'"}
+
+	h := newHarness(t, nil)
+	h.load(commits)
+	h.press("a", "enter")
+	h.wantScreen(screenSummary)
+
+	check := func(hidden bool) {
+		t.Helper()
+		view := h.m.View()
+		content, err := h.m.generateExportContent(entity.FormatText)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, out := range []struct{ name, text string }{{"summary", view}, {"export", content}} {
+			if !strings.Contains(out.text, "Add login") {
+				t.Errorf("%s lost the regular commit:\n%s", out.name, out.text)
+			}
+			for _, merge := range merges {
+				if got := strings.Contains(out.text, merge); got == hidden {
+					t.Errorf("%s shows %q = %v with merges hidden = %v:\n%s", out.name, merge, got, hidden, out.text)
+				}
+			}
+		}
+		if got := strings.Contains(view, "merge commits hidden"); got != hidden {
+			t.Errorf("summary shows the hidden state = %v, want %v", got, hidden)
+		}
+	}
+
+	check(false)
+	h.press("M")
+	check(true)
+	if n := h.github.FetchCount(); n != 0 {
+		t.Errorf("toggling fetched %d times, want none", n)
+	}
+	h.press("M")
+	check(false)
+}
+
+func TestSessionSummary(t *testing.T) {
+	h := newHarness(t, sampleCommits())
+	if got, want := h.m.SessionSummary(), "Fetched 0 ranges, exported 0 files, copied 0 times"; got != want {
+		t.Errorf("SessionSummary() = %q, want %q", got, want)
+	}
+
+	h.choosePreset("today")
+	h.press("enter")
+	h.wantScreen(screenRepoList)
+	h.press("a", "enter")
+	h.wantScreen(screenSummary)
+
+	// Copy from the summary, save a file, then copy from the export screen.
+	h.press("c", "e", "enter", "e", "c")
+	// A failed copy is not counted.
+	h.clipboard.Err = errors.New("no clipboard")
+	h.press("c")
+
+	if h.m.fetchCount != 1 || h.m.exportCount != 1 || h.m.copyCount != 2 {
+		t.Errorf("counts = fetched %d, exported %d, copied %d; want 1, 1, 2", h.m.fetchCount, h.m.exportCount, h.m.copyCount)
+	}
+	if got, want := h.m.SessionSummary(), "Fetched 1 range, exported 1 file, copied 2 times"; got != want {
+		t.Errorf("SessionSummary() = %q, want %q", got, want)
+	}
+}
+
+func TestFavoritesFilter(t *testing.T) {
+	h := newHarness(t, nil, func(cfg *config.Config) {
+		cfg.FavoriteRepos = []string{"octocat/dotfiles", "acme/web", "ghost/unfetched"}
+	})
+	h.load(sampleCommits())
+	indicator := iconFavorite + " favorites only"
+
+	sortedDisplay := func() []string {
+		return slices.Sorted(slices.Values(h.m.getDisplayRepos()))
+	}
+
+	// Off: every repo is listed, favorites pinned first.
+	display := h.m.getDisplayRepos()
+	if len(display) != 3 || display[2] != "acme/api" {
+		t.Errorf("display = %v, want both favorites before acme/api", display)
+	}
+	if strings.Contains(h.m.View(), indicator) {
+		t.Error("view shows the favorites indicator while the filter is off")
+	}
+
+	// On: only fetched favorites; unfetched favorites are not invented.
+	h.press("F")
+	if got, want := sortedDisplay(), []string{"acme/web", "octocat/dotfiles"}; !slices.Equal(got, want) {
+		t.Errorf("favorites only = %v, want %v", got, want)
+	}
+	if !strings.Contains(h.m.View(), indicator) {
+		t.Error("view does not show the favorites indicator")
+	}
+
+	// Intersected with the pattern filter.
+	h.m.filterActive = true
+	h.m.filteredRepos = h.m.commitUC.FilterReposByPattern(h.m.repoList, "acme/*")
+	if got, want := sortedDisplay(), []string{"acme/web"}; !slices.Equal(got, want) {
+		t.Errorf("favorites with filter = %v, want %v", got, want)
+	}
+	h.m.filterActive = false
+
+	// Unfavoriting the highlighted repo drops it from the filter and the config.
+	h.m.cursor = slices.Index(h.m.getDisplayRepos(), "acme/web")
+	h.press("*")
+	if got, want := sortedDisplay(), []string{"octocat/dotfiles"}; !slices.Equal(got, want) {
+		t.Errorf("after unfavoriting = %v, want %v", got, want)
+	}
+	if saved := config.Load(); slices.Contains(saved.FavoriteRepos, "acme/web") {
+		t.Errorf("saved favorite_repos = %v, want acme/web removed", saved.FavoriteRepos)
+	}
+
+	h.press("F")
+	if got := h.m.getDisplayRepos(); len(got) != 3 {
+		t.Errorf("filter off again = %v, want all repos", got)
+	}
+}
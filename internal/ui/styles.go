@@ -3,6 +3,8 @@ package ui
 
 import (
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/DementevVV/commitsum/internal/domain/entity"
 )
 
 // Color palette - modern soft gradients, muted tones.
@@ -35,8 +37,8 @@ var (
 	colorTextSubtle = lipgloss.Color("#4B5563") // Subtle text.
 )
 
-// UI symbols.
-const (
+// UI symbols. useASCII replaces them on terminals without color support.
+var (
 	iconArrowRight = "› "
 	iconCheckBox   = "◉"
 	iconUncheckBox = "○"
@@ -47,6 +49,11 @@ const (
 	iconInfo       = "ℹ"
 	iconBreadcrumb = " › "
 	iconDivider    = "─"
+	iconFavorite   = "★"
+	iconRepo       = "▸ "
+	iconSeparator  = "•"
+	iconMask       = "••••••"
+	iconRange      = entity.RangeSeparator
 
 	// Progress bar characters (using Unicode block elements).
 	barFull     = "█"
@@ -69,6 +76,20 @@ var (
 	styleCommit = lipgloss.NewStyle().
 			Foreground(colorText)
 
+	// Inline tag next to a commit or repository.
+	styleTag = lipgloss.NewStyle().
+			Foreground(colorTextMuted).
+			Italic(true)
+
+	// Badge for repositories that gained commits since the last fetch.
+	styleGainBadge = lipgloss.NewStyle().
+			Foreground(colorSuccess).
+			Bold(true)
+
+	// Star marking favorite repositories.
+	styleFavorite = lipgloss.NewStyle().
+			Foreground(colorWarning)
+
 	// Cursor and selection.
 	styleCursor = lipgloss.NewStyle().
 			Foreground(colorPrimary).
@@ -85,6 +106,12 @@ var (
 	styleHighlight = lipgloss.NewStyle().
 			Foreground(colorAccentLight)
 
+	// Selected text in an input.
+	styleInputSelected = lipgloss.NewStyle().
+				Foreground(colorPrimary).
+				Bold(true).
+				Reverse(true)
+
 	// Footer and help text.
 	styleFooter = lipgloss.NewStyle().
 			Foreground(colorTextMuted).
@@ -97,6 +124,10 @@ var (
 	styleHelpText = lipgloss.NewStyle().
 			Foreground(colorTextMuted)
 
+	styleHelpDisabled = lipgloss.NewStyle().
+				Foreground(colorTextSubtle).
+				Strikethrough(true)
+
 	styleHelpDivider = lipgloss.NewStyle().
 				Foreground(colorTextSubtle).
 				SetString(" │ ")
@@ -105,6 +136,13 @@ var (
 	styleDateLabel = lipgloss.NewStyle().
 			Foreground(colorTextDim)
 
+	// Outcome icons in lists.
+	styleSuccess = lipgloss.NewStyle().
+			Foreground(colorSuccess)
+
+	styleError = lipgloss.NewStyle().
+			Foreground(colorError)
+
 	// Banner-style messages with icons and backgrounds.
 	styleSuccessBanner = lipgloss.NewStyle().
 				Foreground(colorSuccess).
@@ -132,6 +170,10 @@ var (
 	styleStatsLabel = lipgloss.NewStyle().
 			Foreground(colorTextDim)
 
+	// Placeholder rows shown while loading.
+	styleSkeleton = lipgloss.NewStyle().
+			Foreground(colorTextSubtle)
+
 	// Progress bar style for empty portion.
 	styleBarEmpty = lipgloss.NewStyle().
 			Foreground(colorTextSubtle)
@@ -178,3 +220,31 @@ var (
 			Foreground(colorTextDim).
 			Italic(true)
 )
+
+// useASCII swaps Unicode symbols, bars, the date range arrow and rounded
+// borders for plain ASCII. Colors need no handling: lipgloss already drops
+// them for the Ascii profile.
+func useASCII() {
+	iconArrowRight = "> "
+	iconCheckBox = "[x]"
+	iconUncheckBox = "[ ]"
+	iconCommit = "-"
+	iconSuccess = "+"
+	iconWarning = "!"
+	iconError = "x"
+	iconInfo = "i"
+	iconBreadcrumb = " > "
+	iconDivider = "-"
+	iconFavorite = "*"
+	iconRepo = "> "
+	iconSeparator = "-"
+	iconMask = "******"
+	barFull = "#"
+	barEmpty = "."
+	iconRange = "->"
+
+	styleBox = styleBox.Border(lipgloss.ASCIIBorder())
+	styleInputBox = styleInputBox.Border(lipgloss.ASCIIBorder())
+	styleHelpBar = styleHelpBar.BorderStyle(lipgloss.ASCIIBorder())
+	styleHelpDivider = styleHelpDivider.SetString(" | ")
+}
@@ -1,7 +1,10 @@
 package ui
 
 import (
+	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -9,10 +12,47 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/DementevVV/commitsum/internal/domain/entity"
+	"github.com/DementevVV/commitsum/internal/infrastructure/config"
+	"github.com/DementevVV/commitsum/internal/infrastructure/logger"
 )
 
-// Update handles all user interactions and state changes.
+// messageTTL is how long a status message stays on screen.
+const messageTTL = 4 * time.Second
+
+// Update handles all user interactions and state changes. Status messages
+// set while handling msg expire after messageTTL rather than on the next key.
+// The repository list cursor is kept within the displayed list afterwards,
+// whatever changed it.
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if expired, ok := msg.(messageExpiredMsg); ok {
+		if expired.id == m.messageID {
+			m.message = ""
+		}
+		return m, nil
+	}
+
+	prevID := m.messageID
+	model, cmd := m.update(msg)
+	if m.screen == screenRepoList {
+		m.clampCursor()
+	}
+	if m.messageID != prevID && m.message != "" {
+		id := m.messageID
+		cmd = tea.Batch(cmd, tea.Tick(messageTTL, func(time.Time) tea.Msg {
+			return messageExpiredMsg{id: id}
+		}))
+	}
+	return model, cmd
+}
+
+// setMessage shows a status message. Every call starts a new expiry, even
+// when the text is unchanged, so an earlier tick cannot clear it early.
+func (m *Model) setMessage(message string) {
+	m.message = message
+	m.messageID++
+}
+
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		// Global quit.
@@ -20,8 +60,76 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
-		// Clear message on any key.
-		m.message = ""
+		// Clear the export warning on any key; status messages expire on their own.
+		m.exportWarning = ""
+
+		// Global privacy toggle (not while typing into an input).
+		if msg.String() == "P" && !m.isInputScreen() {
+			m.privacyMode = !m.privacyMode
+			return m, nil
+		}
+
+		// Global help bar toggle, e.g. for clean screenshots.
+		if msg.String() == "h" && !m.isInputScreen() {
+			m.hideHelpBar = !m.hideHelpBar
+			return m, nil
+		}
+
+		// Global about screen.
+		if msg.String() == "?" && !m.isInputScreen() && m.screen != screenLoading && m.screen != screenAbout {
+			m.aboutReturn = m.screen
+			m.screen = screenAbout
+			return m, nil
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		return m, nil
+	case authLoginDoneMsg:
+		if msg.err != nil {
+			// Stay on the auth error so the login can be retried.
+			logger.Warn("gh auth login failed", "error", msg.err)
+			m.setMessage("gh auth login did not complete: " + msg.err.Error())
+			return m, nil
+		}
+		logger.LogUserAction("auth_login")
+		return m.loadCommits()
+	case rateLimitLoadedMsg:
+		if msg.err != nil {
+			// Keep the previous status; the quota display is best-effort.
+			logger.Debug("Rate limit check failed", "error", msg.err)
+			return m, nil
+		}
+		m.rateLimit = msg.status
+		m.rateLimitWarning = msg.warning
+		return m, nil
+	case clipboardResultMsg:
+		if msg.err != nil {
+			m.setMessage("Failed to copy: " + msg.err.Error())
+			return m, nil
+		}
+		m.setMessage(msg.success)
+		m.copyCount++
+		if msg.quit {
+			logger.LogUserAction("copy_and_quit")
+			m.exitMessage = msg.success
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
+	if msg, ok := msg.(rangeRefreshedMsg); ok {
+		m.refreshErrs = append(m.refreshErrs, msg.err)
+		if m.refreshing() {
+			return m, m.refreshNextRange()
+		}
+		failed := 0
+		for _, err := range m.refreshErrs {
+			if err != nil {
+				failed++
+			}
+		}
+		logger.LogUserAction("refresh_cached_ranges", "ranges", len(m.refreshRanges), "failed", failed)
+		return m, nil
 	}
 
 	switch m.screen {
@@ -41,6 +149,20 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateStats(msg)
 	case screenLoading:
 		return m.updateLoading(msg)
+	case screenCompareInput:
+		return m.updateCompareInput(msg)
+	case screenCompareRangeInput:
+		return m.updateCompareRangeInput(msg)
+	case screenCompare:
+		return m.updateCompare(msg)
+	case screenProfile:
+		return m.updateProfile(msg)
+	case screenAbout:
+		return m.updateAbout(msg)
+	case screenWizard:
+		return m.updateWizard(msg)
+	case screenConfirmRange:
+		return m.updateConfirmRange(msg)
 	}
 
 	return m, nil
@@ -62,15 +184,23 @@ func (m *Model) updateDateRange(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		case "enter":
 			preset := entity.DateRangePresets[m.dateRangeIdx].Key
+			m.rangePreset = preset
+			m.rangeLabel = ""
 			if preset == "custom" {
 				m.err = nil
 				m.screen = screenDateSelect
+				// Start from today, selected so typing replaces it.
+				m.dateInput.SetValue(entity.Today())
+				m.dateInput.CursorEnd()
+				m.dateInputSelected = true
+				m.dateLimitHit = false
 				m.dateInput.Focus()
 				return m, textinput.Blink
 			}
-			dr := entity.GetDateRange(preset)
+			dr := m.presetRange(preset)
 			m.startDate = dr.StartDate
 			m.endDate = dr.EndDate
+			m.rangeLabel = dr.Label
 			return m.loadCommits()
 		}
 	}
@@ -82,7 +212,11 @@ func (m *Model) updateDateSelect(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyEnter:
-			dateValue := m.dateInput.Value()
+			dateValue := strings.TrimSpace(m.dateInput.Value())
+			if dateValue == "" {
+				m.err = fmt.Errorf("please enter a date (YYYY-MM-DD)")
+				return m, nil
+			}
 			parsedDate, err := time.Parse("2006-01-02", dateValue)
 			if err != nil {
 				m.err = fmt.Errorf("invalid date format, please use YYYY-MM-DD")
@@ -90,12 +224,14 @@ func (m *Model) updateDateSelect(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			// Check that the date is not in the future.
-			now := time.Now()
-			today := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
-			if parsedDate.After(today) {
+			if entity.IsFutureDate(parsedDate) {
 				m.err = fmt.Errorf("date cannot be in the future")
 				return m, nil
 			}
+			if m.config.EarliestDate != "" && dateValue < m.config.EarliestDate {
+				m.err = fmt.Errorf("date cannot be before %s (earliest_date)", m.config.EarliestDate)
+				return m, nil
+			}
 
 			m.startDate = dateValue
 			m.endDate = dateValue
@@ -103,8 +239,37 @@ func (m *Model) updateDateSelect(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.loadCommits()
 		case tea.KeyEsc:
 			m.err = nil
+			m.dateLimitHit = false
+			m.dateInputSelected = false
 			m.screen = screenDateRange
 			return m, nil
+		case tea.KeyBackspace:
+			m.dateLimitHit = false
+			if m.dateInputSelected {
+				m.dateInputSelected = false
+				m.dateInput.SetValue("")
+				return m, nil
+			}
+		case tea.KeyRunes:
+			// Pasted dates often carry surrounding whitespace; drop it so
+			// that it doesn't count against the limit.
+			if msg.Paste {
+				msg.Runes = []rune(strings.TrimSpace(string(msg.Runes)))
+			}
+			if m.dateInputSelected {
+				m.dateInputSelected = false
+				m.dateInput.SetValue("")
+			}
+			// The input keeps only the first dateInputLimit characters;
+			// flag the overflow instead of dropping it silently.
+			m.dateLimitHit = len([]rune(m.dateInput.Value()))+len(msg.Runes) > dateInputLimit
+			// Forward the trimmed key; msg shadows the original message.
+			var cmd tea.Cmd
+			m.dateInput, cmd = m.dateInput.Update(msg)
+			return m, cmd
+		default:
+			m.dateLimitHit = false
+			m.dateInputSelected = false
 		}
 	}
 
@@ -130,9 +295,7 @@ func (m *Model) updateRepoFilter(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.screen = screenRepoList
 			return m, nil
 		case tea.KeyEsc:
-			m.filterActive = false
-			m.filterInput.SetValue("")
-			m.filteredRepos = m.repoList
+			m.clearFilter()
 			m.screen = screenRepoList
 			return m, nil
 		}
@@ -145,6 +308,8 @@ func (m *Model) updateRepoFilter(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m *Model) updateRepoList(msg tea.Msg) (tea.Model, tea.Cmd) {
 	repos := m.getDisplayRepos()
+	// Guard against a cursor left past the end by a shrinking list.
+	m.cursor = clampIndex(m.cursor, len(repos))
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -152,13 +317,33 @@ func (m *Model) updateRepoList(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "q":
 			return m, tea.Quit
 		case "enter":
+			if m.nudgeEmptyFilter(repos) {
+				break
+			}
 			m.screen = screenSummary
-			m.stats = m.commitUC.CalculateStatistics(m.commits, m.selected)
+			m.gained = nil
+			m.stats = m.summaryStats()
+			// Record what is shown; the new markers stay until the next load.
+			if err := m.commitUC.MarkSeen(m.startDate, m.endDate, m.summaryCommits(), m.summarySelection()); err != nil {
+				logger.Warn("Failed to record seen commits", "error", err)
+			}
+			if m.config.AutoCopy {
+				return m, m.copySummary(false)
+			}
 		case " ":
 			if len(repos) > 0 {
 				currentRepo := repos[m.cursor]
 				m.selected[currentRepo] = !m.selected[currentRepo]
 			}
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9", "0":
+			// Quick pick: toggle the repo at that display position (0 is the tenth).
+			idx := int(msg.String()[0]-'0') - 1
+			if idx < 0 {
+				idx = 9
+			}
+			if idx < len(repos) {
+				m.selected[repos[idx]] = !m.selected[repos[idx]]
+			}
 		case "j", "down":
 			if m.cursor < len(repos)-1 {
 				m.cursor++
@@ -177,16 +362,120 @@ func (m *Model) updateRepoList(msg tea.Msg) (tea.Model, tea.Cmd) {
 			for _, repo := range repos {
 				m.selected[repo] = false
 			}
+		case "esc":
+			// Clear an active filter in place, keeping the highlighted repo.
+			if !m.filterActive {
+				break
+			}
+			var current string
+			if len(repos) > 0 {
+				current = repos[m.cursor]
+			}
+			m.clearFilter()
+			m.cursor = max(0, slices.Index(m.getDisplayRepos(), current))
+			m.setMessage("Filter cleared")
 		case "f", "/":
 			m.screen = screenRepoFilter
 			m.filterInput.Focus()
 			return m, textinput.Blink
 		case "s":
+			if m.nudgeEmptyFilter(repos) {
+				break
+			}
 			// Stats.
-			m.stats = m.commitUC.CalculateStatistics(m.commits, m.selected)
+			m.stats = m.summaryStats()
 			m.screen = screenStats
+		case "p":
+			// Save or apply a selection profile.
+			m.err = nil
+			m.screen = screenProfile
+			m.profileInput.Focus()
+			return m, textinput.Blink
+		case "o":
+			// Cycle repository sort order.
+			m.repoSort = nextRepoSort(m.repoSort)
+			m.cursor = 0
+		case "v":
+			// Compare with a teammate.
+			m.err = nil
+			m.compareFrom = screenRepoList
+			m.screen = screenCompareInput
+			m.teammateInput.Focus()
+			return m, textinput.Blink
+		case "t":
+			// Toggle date field and re-fetch the current range.
+			field := m.commitUC.ToggleDateField()
+			logger.LogUserAction("toggle_date_field", "field", string(field))
+			m.resetCountGains()
+			return m.loadCommits()
+		case "%":
+			m.showPercent = !m.showPercent
+		case "F":
+			// Show only favorite repos (intersected with what was fetched).
+			m.favoritesOnly = !m.favoritesOnly
+			m.cursor = 0
+		case "*":
+			if len(repos) == 0 {
+				break
+			}
+			// Keep the cursor on the repo as it moves to or from the top.
+			repo := repos[m.cursor]
+			m.toggleFavorite(repo)
+			m.cursor = max(0, slices.Index(m.getDisplayRepos(), repo))
+		case "x":
+			// Temporarily expand every selected repo, ignoring max_expanded_repos.
+			m.expandAll = !m.expandAll
+		case "B":
+			// Recover from the result cap by re-fetching in smaller ranges.
+			if m.canRecoverCap() {
+				logger.LogUserAction("bisect_capped_range")
+				return m.fetchCommits(m.commitUC.GetCommitsBisected)
+			}
+		case "D":
+			// Recover from the result cap by narrowing to the last day.
+			if m.canRecoverCap() {
+				logger.LogUserAction("narrow_capped_range")
+				m.startDate = m.endDate
+				m.rangeLabel = ""
+				return m.loadCommits()
+			}
+		case "z":
+			// Collapse or expand the highlighted repo's owner group (remembered).
+			if len(repos) > 0 {
+				m.toggleOwnerCollapsed(repos[m.cursor])
+			}
+		case "Z":
+			m.collapseAllOwners()
+		case "E":
+			m.setCollapsedOwners(nil)
+		case "T":
+			// Toggle whether week/month presets include today, and remember it.
+			if !m.canExcludeToday() {
+				break
+			}
+			m.config.ExcludeToday = !m.config.ExcludeToday
+			if err := config.Save(m.config); err != nil {
+				logger.Warn("Failed to save exclude_today preference", "error", err)
+			}
+			logger.LogUserAction("toggle_exclude_today", "exclude", m.config.ExcludeToday)
+			dr := m.presetRange(m.rangePreset)
+			m.startDate = dr.StartDate
+			m.endDate = dr.EndDate
+			m.rangeLabel = dr.Label
+			return m.loadCommits()
+		case "l":
+			// Log in again without leaving the app, then retry the fetch.
+			if !m.canLogin() {
+				break
+			}
+			// The account may change, so look the user up again afterwards.
+			m.commitUC.ForgetUser()
+			return m, tea.ExecProcess(m.loginCommand(), func(err error) tea.Msg {
+				return authLoginDoneMsg{err: err}
+			})
 		case "r":
 			// Refresh - go back to date selection.
+			m.gained = nil
 			m.err = nil
 			m.screen = screenDateRange
 			m.cursor = 0
@@ -204,25 +493,73 @@ func (m *Model) updateSummary(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "esc", "b":
 			m.screen = screenRepoList
 		case "c":
-			content, err := m.generateExportContent(entity.FormatText)
+			return m, m.copySummary(false)
+		case "Q":
+			// Copy and quit in one step; stay on screen if the copy fails.
+			if len(m.commitUC.GetSelectedReposSorted(m.summaryCommits(), m.summarySelection())) == 0 {
+				m.exitMessage = "Nothing selected, quitting without copying"
+				return m, tea.Quit
+			}
+			return m, m.copySummary(true)
+		case "Y":
+			// Copy just the selected repository names, one per line.
+			repos := m.commitUC.GetSelectedReposSorted(m.summaryCommits(), m.summarySelection())
+			if len(repos) == 0 {
+				m.setMessage("No repositories selected")
+				break
+			}
+			return m, m.copyCmd(strings.Join(repos, "\n"), fmt.Sprintf("Copied %d repository %s!", len(repos), pluralize(len(repos), "name", "names")))
+		case "C":
+			// Copy the one-line compact digest.
+			content, err := m.generateExportContent(entity.FormatCompact)
 			if err != nil {
-				m.message = "Failed to generate content: " + err.Error()
-			} else if err := m.clipboard.Copy(content); err != nil {
-				m.message = "Failed to copy: " + err.Error()
-			} else {
-				m.message = "Copied to clipboard!"
+				m.setMessage("Failed to generate content: " + err.Error())
+				break
 			}
+			return m, m.copyCmd(content, "Copied compact summary to clipboard!")
 		case "e":
 			m.screen = screenExport
-			m.exportFormat = 0
+			m.exportFormat = indexOf(m.exportFormats, m.config.OutputFormat)
 		case "s":
-			m.stats = m.commitUC.CalculateStatistics(m.commits, m.selected)
+			m.stats = m.summaryStats()
 			m.screen = screenStats
+		case "M":
+			// Show or hide merge commits without re-fetching.
+			m.hideMerges = !m.hideMerges
+		case "+":
+			m.minCommits++
+		case "-":
+			if m.minCommits > 0 {
+				m.minCommits--
+			}
 		}
 	}
 	return m, nil
 }
 
+// copySummary copies the plain-text summary, optionally quitting once the
+// copy succeeds. Content errors are reported immediately.
+func (m *Model) copySummary(quit bool) tea.Cmd {
+	content, err := m.generateExportContent(entity.FormatText)
+	if err != nil {
+		m.setMessage("Failed to generate content: " + err.Error())
+		return nil
+	}
+	clip := m.clipboard
+	return func() tea.Msg {
+		return clipboardResultMsg{success: "Copied to clipboard!", quit: quit, err: clip.Copy(content)}
+	}
+}
+
+// copyCmd copies content in the background and reports the outcome with a
+// clipboardResultMsg, shown as success or as a failure banner.
+func (m *Model) copyCmd(content, success string) tea.Cmd {
+	clip := m.clipboard
+	return func() tea.Msg {
+		return clipboardResultMsg{success: success, err: clip.Copy(content)}
+	}
+}
+
 func (m *Model) updateExport(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -239,11 +576,16 @@ func (m *Model) updateExport(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.exportFormat > 0 {
 				m.exportFormat--
 			}
+		case "f":
+			// Restrict the export to selected repos matching the active filter.
+			if m.filterActive {
+				m.exportFilteredOnly = !m.exportFilteredOnly
+			}
 		case "enter":
 			format := entity.ExportFormat(m.exportFormats[m.exportFormat])
 			content, err := m.generateExportContent(format)
 			if err != nil {
-				m.message = "Failed to generate content: " + err.Error()
+				m.setMessage("Failed to generate content: " + err.Error())
 				m.screen = screenSummary
 				return m, nil
 			}
@@ -251,21 +593,20 @@ func (m *Model) updateExport(msg tea.Msg) (tea.Model, tea.Cmd) {
 			filename := m.exportUC.GenerateFilename(m.startDate, format)
 
 			if err := m.exportUC.SaveToFile(content, filename); err != nil {
-				m.message = "Failed to save: " + err.Error()
+				m.setMessage("Failed to save: " + err.Error())
 			} else {
-				m.message = "Saved to " + filename
+				m.setMessage("Saved to " + filename)
+				m.exportCount++
 			}
 			m.screen = screenSummary
 		case "c":
 			format := entity.ExportFormat(m.exportFormats[m.exportFormat])
 			content, err := m.generateExportContent(format)
 			if err != nil {
-				m.message = "Failed to generate content: " + err.Error()
-			} else if err := m.clipboard.Copy(content); err != nil {
-				m.message = "Failed to copy: " + err.Error()
-			} else {
-				m.message = "Copied to clipboard!"
+				m.setMessage("Failed to generate content: " + err.Error())
+				break
 			}
+			return m, m.copyCmd(content, "Copied to clipboard!")
 		}
 	}
 	return m, nil
@@ -279,40 +620,279 @@ func (m *Model) updateStats(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case "esc", "b":
 			m.screen = screenRepoList
+		case "c":
+			if m.stats == nil {
+				m.setMessage("No statistics to copy")
+				break
+			}
+			data, err := json.MarshalIndent(m.stats, "", "  ")
+			if err != nil {
+				m.setMessage("Failed to generate content: " + err.Error())
+				break
+			}
+			return m, m.copyCmd(string(data), "Copied statistics JSON to clipboard!")
+		case "v":
+			// Compare the current range with another one.
+			m.err = nil
+			m.compareFrom = screenStats
+			m.screen = screenCompareRangeInput
+			m.compareRangeInput.Focus()
+			return m, textinput.Blink
 		}
 	}
 	return m, nil
 }
 
+func (m *Model) updateCompareInput(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyEnter:
+			teammate := strings.TrimSpace(m.teammateInput.Value())
+			if teammate == "" {
+				m.err = fmt.Errorf("please enter a GitHub username")
+				return m, nil
+			}
+			m.err = nil
+			return m.loadComparison(teammate)
+		case tea.KeyEsc:
+			m.err = nil
+			m.screen = screenRepoList
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.teammateInput, cmd = m.teammateInput.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) updateCompareRangeInput(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyEnter:
+			other, err := entity.ParseRange(m.compareRangeInput.Value())
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.err = nil
+			return m.loadRangeComparison(other)
+		case tea.KeyEsc:
+			m.err = nil
+			m.screen = screenStats
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.compareRangeInput, cmd = m.compareRangeInput.Update(msg)
+	return m, cmd
+}
+
+// updateProfile applies the named profile on enter and saves the current
+// selection under the name on ctrl+s.
+func (m *Model) updateProfile(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		name := strings.TrimSpace(m.profileInput.Value())
+		switch msg.Type {
+		case tea.KeyEnter:
+			if name == "" {
+				m.err = fmt.Errorf("please enter a profile name")
+				return m, nil
+			}
+			inactive, ok := m.applyProfile(name)
+			if !ok {
+				m.err = fmt.Errorf("no profile named %q", name)
+				return m, nil
+			}
+			m.err = nil
+			message := fmt.Sprintf("Applied profile %q", name)
+			if len(inactive) > 0 {
+				message += " " + iconSeparator + " no commits in range: " + m.repoLabels(inactive)
+			}
+			m.setMessage(message)
+			m.screen = screenRepoList
+			return m, nil
+		case tea.KeyCtrlS:
+			if name == "" {
+				m.err = fmt.Errorf("please enter a profile name")
+				return m, nil
+			}
+			m.err = nil
+			m.setMessage(fmt.Sprintf("Saved %d repositories as profile %q", m.saveProfile(name), name))
+			m.screen = screenRepoList
+			return m, nil
+		case tea.KeyEsc:
+			m.err = nil
+			m.screen = screenRepoList
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.profileInput, cmd = m.profileInput.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) updateCompare(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc", "b":
+			m.screen = m.compareFrom
+		case "c":
+			data, err := json.MarshalIndent(m.comparison, "", "  ")
+			if err != nil {
+				m.setMessage("Failed to generate content: " + err.Error())
+				break
+			}
+			return m, m.copyCmd(string(data), "Copied comparison JSON to clipboard!")
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) loadComparison(teammate string) (*Model, tea.Cmd) {
+	m.loading = true
+	m.screen = screenLoading
+	m.err = nil
+
+	startDate, endDate := m.startDate, m.endDate
+	return m, tea.Batch(
+		m.spinner.Tick,
+		func() tea.Msg {
+			comparison, err := m.commitUC.CompareWithAuthor(teammate, startDate, endDate)
+			return comparisonLoadedMsg{comparison: comparison, input: screenCompareInput, err: err}
+		},
+	)
+}
+
+// loadRangeComparison compares the current range with other.
+func (m *Model) loadRangeComparison(other entity.DateRange) (*Model, tea.Cmd) {
+	m.loading = true
+	m.screen = screenLoading
+	m.err = nil
+
+	current := entity.DateRange{StartDate: m.startDate, EndDate: m.endDate}
+	return m, tea.Batch(
+		m.spinner.Tick,
+		func() tea.Msg {
+			comparison, err := m.commitUC.CompareRanges(current, other)
+			return comparisonLoadedMsg{comparison: comparison, input: screenCompareRangeInput, err: err}
+		},
+	)
+}
+
 func (m *Model) loadCommits() (*Model, tea.Cmd) {
+	rangeKey := m.startDate + ".." + m.endDate
+	if limit := m.config.LargeRangeDays; limit > 0 && rangeKey != m.confirmedRange &&
+		entity.RangeDays(m.startDate, m.endDate) > limit {
+		m.screen = screenConfirmRange
+		return m, nil
+	}
+
+	return m.fetchCommits(m.commitUC.GetCommitsForRange)
+}
+
+// fetchCommits shows the loading screen and loads the current range with
+// fetch, reporting the result as a commitsLoadedMsg.
+func (m *Model) fetchCommits(fetch func(startDate, endDate string) (*entity.CommitData, error)) (*Model, tea.Cmd) {
 	m.loading = true
 	m.screen = screenLoading
 	m.err = nil
+	m.placeholderRepos = m.commitUC.CachedRepoList(m.startDate, m.endDate)
 
 	return m, tea.Batch(
 		m.spinner.Tick,
 		func() tea.Msg {
-			data, err := m.commitUC.GetCommitsForRange(m.startDate, m.endDate)
+			data, err := fetch(m.startDate, m.endDate)
 			if err != nil {
 				return commitsLoadedMsg{err: err}
 			}
 			return commitsLoadedMsg{
-				commits:  data.Commits,
-				repoList: data.RepoList,
-				warning:  data.Warning,
-				err:      nil,
+				commits:   data.Commits,
+				repoList:  data.RepoList,
+				warning:   data.Warning,
+				fetchedAt: data.FetchedAt,
+				dropped:   data.ExcludedByBranch + data.ExcludedByWeekday + data.DroppedEmpty,
+				seen:      m.commitUC.SeenSHAs(m.startDate, m.endDate),
+				capped:    data.Capped,
+				err:       nil,
 			}
 		},
 	)
 }
 
+// updateConfirmRange asks before loading a range longer than large_range_days.
+func (m *Model) updateConfirmRange(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "y", "enter":
+			m.confirmedRange = m.startDate + ".." + m.endDate
+			logger.LogUserAction("confirm_large_range", "days", entity.RangeDays(m.startDate, m.endDate))
+			return m.loadCommits()
+		case "n", "esc":
+			m.screen = screenDateRange
+		case "q":
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+// loadRateLimit checks the search API quota in the background.
+func (m *Model) loadRateLimit() tea.Cmd {
+	return func() tea.Msg {
+		status, warning, err := m.commitUC.RateLimitStatus()
+		return rateLimitLoadedMsg{status: status, warning: warning, err: err}
+	}
+}
+
+// trackCountGains diffs the loaded counts against the previous fetch of the
+// same range and remembers the new counts.
+func (m *Model) trackCountGains() {
+	current := commitCounts(m.commits)
+	rangeKey := m.startDate + ".." + m.endDate
+	if rangeKey == m.loadedRange && m.prevCounts != nil {
+		m.gained = countGains(m.prevCounts, current)
+	} else {
+		m.gained = nil
+	}
+	m.loadedRange = rangeKey
+	m.prevCounts = current
+}
+
+// resetCountGains forgets the previous fetch, so the next one starts a new
+// baseline instead of being diffed against counts taken under other
+// search parameters.
+func (m *Model) resetCountGains() {
+	m.loadedRange = ""
+	m.prevCounts = nil
+	m.gained = nil
+}
+
 func (m *Model) updateLoading(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case commitsLoadedMsg:
 		m.loading = false
+		m.placeholderRepos = nil
 		m.commits = msg.commits
 		m.repoList = msg.repoList
+		if msg.err == nil {
+			m.trackCountGains()
+		}
 		m.warning = msg.warning
+		m.fetchedAt = msg.fetchedAt
+		m.droppedCommits = msg.dropped
+		if msg.err == nil {
+			m.fetchCount++
+		}
+		m.seen = msg.seen
+		m.capped = msg.capped
 		if m.filterInput.Value() != "" {
 			m.filterActive = true
 			m.filteredRepos = m.commitUC.FilterReposByPattern(m.repoList, m.filterInput.Value())
@@ -323,6 +903,16 @@ func (m *Model) updateLoading(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.err = msg.err
 		m.screen = screenRepoList
 		m.cursor = 0
+		return m, m.loadRateLimit()
+	case comparisonLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			m.screen = msg.input
+			return m, nil
+		}
+		m.comparison = msg.comparison
+		m.screen = screenCompare
 		return m, nil
 	case spinner.TickMsg:
 		var cmd tea.Cmd
@@ -342,3 +932,155 @@ func (m *Model) updateLoading(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 	return m, nil
 }
+
+func (m *Model) updateAbout(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		if m.confirmClearCache {
+			m.confirmClearCache = false
+			if msg.String() == "y" {
+				m.clearCache()
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "d":
+			return m, m.copyPath("Cache directory", m.commitUC.CacheDir())
+		case "c":
+			path, _ := config.Path()
+			return m, m.copyPath("Config path", path)
+		case "l":
+			return m, m.copyPath("Log path", logger.Path())
+		case "W":
+			if !m.refreshing() {
+				return m, m.startCacheRefresh()
+			}
+		case "X":
+			if m.config.ConfirmDestructive {
+				m.confirmClearCache = true
+			} else {
+				m.clearCache()
+			}
+		default:
+			m.screen = m.aboutReturn
+		}
+	}
+	return m, nil
+}
+
+// clearCache removes all cached commits and reports the outcome.
+func (m *Model) clearCache() {
+	if err := m.commitUC.ClearCache(); err != nil {
+		m.setMessage("Failed to clear cache: " + err.Error())
+		return
+	}
+	logger.LogUserAction("clear_cache")
+	m.setMessage("Cache cleared")
+}
+
+// nudgeEmptyFilter reports whether the filter hides every repository, in
+// which case it points the user at clearing the filter instead of opening an
+// empty summary or statistics screen.
+func (m *Model) nudgeEmptyFilter(repos []string) bool {
+	if len(repos) > 0 || !m.filterActive {
+		return false
+	}
+	m.setMessage("No repos match the filter; press esc to clear it")
+	return true
+}
+
+// startCacheRefresh refetches every date range preset into the cache. The
+// ranges run one after another to stay within the search rate limit.
+func (m *Model) startCacheRefresh() tea.Cmd {
+	m.refreshRanges = nil
+	m.refreshErrs = nil
+	for _, preset := range entity.DateRangePresets {
+		if preset.Key == "custom" {
+			continue
+		}
+		m.refreshRanges = append(m.refreshRanges, refreshRange{
+			label:     preset.Label,
+			DateRange: m.presetRange(preset.Key),
+		})
+	}
+	return m.refreshNextRange()
+}
+
+// refreshNextRange refetches the first range without a result.
+func (m *Model) refreshNextRange() tea.Cmd {
+	r := m.refreshRanges[len(m.refreshErrs)]
+	return func() tea.Msg {
+		return rangeRefreshedMsg{err: m.commitUC.RefreshRange(r.StartDate, r.EndDate)}
+	}
+}
+
+// copyPath copies a file system path for support requests.
+func (m *Model) copyPath(label, path string) tea.Cmd {
+	if path == "" {
+		m.setMessage(label + " is not available")
+		return nil
+	}
+	return m.copyCmd(path, label+" copied!")
+}
+
+func (m *Model) updateWizard(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	options := m.wizardOptions()
+	switch keyMsg.String() {
+	case "q":
+		return m, tea.Quit
+	case "j", "down":
+		if m.wizardCursor < len(options)-1 {
+			m.wizardCursor++
+		}
+	case "k", "up":
+		if m.wizardCursor > 0 {
+			m.wizardCursor--
+		}
+	case "esc":
+		// Skip: keep defaults but save them so the wizard does not return.
+		m.finishWizard()
+	case "enter":
+		choice := options[m.wizardCursor]
+		switch m.wizardStep {
+		case wizardStepDateRange:
+			m.config.DefaultDateRange = choice
+		case wizardStepOutputFormat:
+			m.config.OutputFormat = choice
+		case wizardStepAutoCopy:
+			m.config.AutoCopy = choice == "yes"
+		}
+
+		m.wizardStep++
+		if m.wizardStep == wizardStepCount {
+			m.finishWizard()
+			return m, nil
+		}
+		current := m.config.OutputFormat
+		if m.wizardStep == wizardStepAutoCopy {
+			current = "no"
+			if m.config.AutoCopy {
+				current = "yes"
+			}
+		}
+		m.wizardCursor = indexOf(m.wizardOptions(), current)
+	}
+	return m, nil
+}
+
+// finishWizard saves the chosen settings and continues to date selection.
+func (m *Model) finishWizard() {
+	if err := config.Save(m.config); err != nil {
+		logger.Warn("Failed to save config from setup wizard", "error", err)
+	}
+	logger.LogUserAction("setup_wizard_completed", "default_date_range", m.config.DefaultDateRange,
+		"output_format", m.config.OutputFormat, "auto_copy", m.config.AutoCopy)
+	m.dateRangeIdx = defaultPresetIndex(m.config.DefaultDateRange)
+	m.screen = screenDateRange
+}
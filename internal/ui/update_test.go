@@ -0,0 +1,860 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/DementevVV/commitsum/internal/domain/entity"
+	"github.com/DementevVV/commitsum/internal/domain/repository"
+	"github.com/DementevVV/commitsum/internal/infrastructure/config"
+	"github.com/DementevVV/commitsum/internal/testutil"
+)
+
+// choosePreset moves the date range cursor onto the preset with key.
+func (h *harness) choosePreset(key string) {
+	h.t.Helper()
+	for range entity.DateRangePresets {
+		h.press("up")
+	}
+	for range presetIndex(h.t, key) {
+		h.press("down")
+	}
+}
+
+func TestHappyPath(t *testing.T) {
+	h := newHarness(t, sampleCommits())
+	h.wantScreen(screenDateRange)
+
+	h.choosePreset("today")
+	h.press("enter")
+	h.wantScreen(screenRepoList)
+	h.wantCursor(0)
+	if h.github.FetchCount() == 0 {
+		t.Fatal("no commits fetched")
+	}
+	if got := h.selectedRepos(); len(got) != 0 {
+		t.Fatalf("selected = %v, want none", got)
+	}
+
+	// Select the second repository only.
+	h.press("down", " ")
+	h.wantCursor(1)
+	if got, want := h.selectedRepos(), []string{h.m.getDisplayRepos()[1]}; !slices.Equal(got, want) {
+		t.Fatalf("selected = %v, want %v", got, want)
+	}
+
+	h.press("enter")
+	h.wantScreen(screenSummary)
+
+	h.press("e")
+	h.wantScreen(screenExport)
+
+	h.press("enter")
+	h.wantScreen(screenSummary)
+	filename, ok := strings.CutPrefix(h.m.message, "Saved to ")
+	if !ok {
+		t.Fatalf("message = %q, want a saved file", h.m.message)
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "Style header") {
+		t.Errorf("export lacks the selected repository's commit:\n%s", content)
+	}
+	if strings.Contains(string(content), "Add login") {
+		t.Errorf("export contains an unselected repository's commit:\n%s", content)
+	}
+}
+
+func TestCommitsLoaded(t *testing.T) {
+	h := newHarness(t, nil)
+	h.m.screen = screenLoading
+	h.m.cursor = 5
+
+	commits := sampleCommits()
+	h.load(commits)
+
+	h.wantScreen(screenRepoList)
+	h.wantCursor(0)
+	if h.m.loading {
+		t.Error("still loading")
+	}
+	if got := len(h.m.getDisplayRepos()); got != len(commits) {
+		t.Errorf("displayed %d repos, want %d", got, len(commits))
+	}
+	if got := h.selectedRepos(); len(got) != 0 {
+		t.Errorf("selected = %v, want none", got)
+	}
+
+	h.press("a")
+	if got := h.selectedRepos(); len(got) != len(commits) {
+		t.Errorf("selected %d repos after select all, want %d", len(got), len(commits))
+	}
+	h.press("n")
+	if got := h.selectedRepos(); len(got) != 0 {
+		t.Errorf("selected = %v after select none, want none", got)
+	}
+}
+
+func TestCustomDateValidation(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+
+	tests := []struct {
+		name    string
+		input   string
+		earlier string
+		wantErr string
+	}{
+		{name: "malformed", input: "2024/01/02", wantErr: "invalid date format, please use YYYY-MM-DD"},
+		{name: "impossible day", input: "2024-02-30", wantErr: "invalid date format, please use YYYY-MM-DD"},
+		{name: "future", input: tomorrow, wantErr: "date cannot be in the future"},
+		{name: "before earliest", input: "2019-12-31", earlier: "2020-01-01", wantErr: "date cannot be before 2020-01-01 (earliest_date)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, sampleCommits())
+			h.m.config.EarliestDate = tt.earlier
+
+			h.choosePreset("custom")
+			h.press("enter")
+			h.wantScreen(screenDateSelect)
+
+			h.typeText(tt.input)
+			h.press("enter")
+
+			h.wantScreen(screenDateSelect)
+			if h.m.err == nil || h.m.err.Error() != tt.wantErr {
+				t.Fatalf("err = %v, want %q", h.m.err, tt.wantErr)
+			}
+			if n := h.github.FetchCount(); n != 0 {
+				t.Errorf("fetched %d times, want none", n)
+			}
+
+			h.press("esc")
+			h.wantScreen(screenDateRange)
+			if h.m.err != nil {
+				t.Errorf("err = %v after esc, want nil", h.m.err)
+			}
+		})
+	}
+}
+
+func TestCustomDateLoads(t *testing.T) {
+	h := newHarness(t, sampleCommits())
+	h.choosePreset("custom")
+	h.press("enter")
+
+	h.typeText("2024-03-01")
+	h.press("enter")
+
+	h.wantScreen(screenRepoList)
+	if h.m.startDate != "2024-03-01" || h.m.endDate != "2024-03-01" {
+		t.Errorf("range = %s..%s, want 2024-03-01..2024-03-01", h.m.startDate, h.m.endDate)
+	}
+}
+
+func TestCustomDateSelectedOnEntry(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		want string
+	}{
+		{name: "untouched", want: entity.Today()},
+		{name: "typing replaces", keys: []string{"2"}, want: "2"},
+		{name: "backspace clears", keys: []string{"backspace"}, want: ""},
+		{name: "editing after a move", keys: []string{"left", "backspace"}, want: entity.Today()[:8] + entity.Today()[9:]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, sampleCommits())
+			h.choosePreset("custom")
+			h.press("enter")
+			if !h.m.dateInputSelected {
+				t.Fatal("date is not selected on entry")
+			}
+
+			h.press(tt.keys...)
+			if got := h.m.dateInput.Value(); got != tt.want {
+				t.Errorf("value = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCustomDateLimit(t *testing.T) {
+	paste := func(text string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text), Paste: true}
+	}
+	const hint = "extra input was ignored"
+
+	tests := []struct {
+		name     string
+		typed    string
+		keys     []string
+		paste    string
+		want     string
+		wantHint bool
+	}{
+		{name: "at the limit", typed: "2024-03-01", want: "2024-03-01"},
+		{name: "one past the limit", typed: "2024-03-011", want: "2024-03-01", wantHint: true},
+		{name: "backspace clears the hint", typed: "2024-03-011", keys: []string{"backspace"}, want: "2024-03-0"},
+		{name: "long paste is truncated", paste: "2024-03-01T10:00", want: "2024-03-01", wantHint: true},
+		{name: "paste whitespace is trimmed", paste: " 2024-03-01\n", want: "2024-03-01"},
+		{name: "paste into a full input", typed: "2024-03-01", paste: "5", want: "2024-03-01", wantHint: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, sampleCommits())
+			h.choosePreset("custom")
+			h.press("enter")
+
+			h.typeText(tt.typed)
+			if tt.paste != "" {
+				h.send(paste(tt.paste))
+			}
+			h.press(tt.keys...)
+
+			if got := h.m.dateInput.Value(); got != tt.want {
+				t.Errorf("value = %q, want %q", got, tt.want)
+			}
+			if h.m.dateLimitHit != tt.wantHint {
+				t.Errorf("dateLimitHit = %v, want %v", h.m.dateLimitHit, tt.wantHint)
+			}
+			if got := strings.Contains(h.m.View(), hint); got != tt.wantHint {
+				t.Errorf("limit hint shown = %v, want %v", got, tt.wantHint)
+			}
+		})
+	}
+}
+
+func TestCustomDateEmptyInput(t *testing.T) {
+	h := newHarness(t, sampleCommits())
+	h.choosePreset("custom")
+	h.press("enter")
+
+	h.press("backspace", "enter")
+
+	h.wantScreen(screenDateSelect)
+	const want = "please enter a date (YYYY-MM-DD)"
+	if h.m.err == nil || h.m.err.Error() != want {
+		t.Fatalf("err = %v, want %q", h.m.err, want)
+	}
+	if !h.m.dateInput.Focused() {
+		t.Error("date input lost focus")
+	}
+	if n := h.github.FetchCount(); n != 0 {
+		t.Errorf("fetched %d times, want none", n)
+	}
+}
+
+func TestToggleDateField(t *testing.T) {
+	h := newHarness(t, sampleCommits())
+	h.choosePreset("today")
+	h.press("enter")
+	h.wantScreen(screenRepoList)
+	initial := h.github.FetchCount()
+
+	// Each field is fetched once; toggling back is served from the cache,
+	// which keys entries by field.
+	steps := []struct {
+		wantField   entity.DateField
+		wantFetches int
+	}{
+		{wantField: entity.DateFieldAuthor, wantFetches: initial + 1},
+		{wantField: entity.DateFieldCommitter, wantFetches: initial + 1},
+		{wantField: entity.DateFieldAuthor, wantFetches: initial + 1},
+	}
+	for i, step := range steps {
+		h.press("t")
+		h.wantScreen(screenRepoList)
+		if got := h.commitUC.DateField(); got != step.wantField {
+			t.Fatalf("step %d: field = %v, want %v", i, got, step.wantField)
+		}
+		if got := h.github.FetchCount(); got != step.wantFetches {
+			t.Fatalf("step %d: %d fetches, want %d", i, got, step.wantFetches)
+		}
+		if !strings.Contains(h.m.View(), "by "+step.wantField.Label()) {
+			t.Errorf("step %d: view lacks the %q indicator", i, step.wantField.Label())
+		}
+	}
+	if last := h.github.Fetches[len(h.github.Fetches)-1]; last.Field != entity.DateFieldAuthor {
+		t.Errorf("refetch used field %v, want %v", last.Field, entity.DateFieldAuthor)
+	}
+}
+
+func TestDigitTogglesRepo(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		keys   []string
+		want   []string
+	}{
+		{name: "first", keys: []string{"1"}, want: []string{"acme/api"}},
+		{name: "third", keys: []string{"3"}, want: []string{"octocat/dotfiles"}},
+		{name: "toggled twice", keys: []string{"2", "2"}, want: nil},
+		{name: "past the end", keys: []string{"4", "0"}, want: nil},
+		{name: "filtered order", filter: "*web*", keys: []string{"1"}, want: []string{"acme/web"}},
+		{name: "outside the filter", filter: "*web*", keys: []string{"2"}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, nil)
+			h.load(sampleCommits())
+			if tt.filter != "" {
+				h.press("f")
+				h.m.filterInput.SetValue("")
+				h.typeText(tt.filter)
+				h.press("enter")
+				h.wantScreen(screenRepoList)
+			}
+
+			h.press(tt.keys...)
+			if got := h.selectedRepos(); !slices.Equal(got, tt.want) {
+				t.Errorf("selected = %v, want %v", got, tt.want)
+			}
+			if len(tt.want) == 0 && len(h.m.selected) > 0 {
+				for repo, on := range h.m.selected {
+					if on {
+						t.Errorf("%s selected outside the display", repo)
+					}
+				}
+			}
+			h.wantCursor(0)
+		})
+	}
+}
+
+func TestCopyAndQuit(t *testing.T) {
+	tests := []struct {
+		name        string
+		selected    []string
+		copyErr     error
+		wantCopy    bool
+		wantQuit    bool
+		wantMessage string
+		wantExit    string
+	}{
+		{name: "copies and quits", selected: []string{"acme/api"}, wantCopy: true, wantQuit: true, wantMessage: "Copied to clipboard!", wantExit: "Copied to clipboard!"},
+		{name: "nothing selected", wantQuit: true, wantExit: "Nothing selected, quitting without copying"},
+		{name: "copy fails", selected: []string{"acme/api"}, copyErr: errors.New("no clipboard"), wantMessage: "Failed to copy: no clipboard"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, nil)
+			h.clipboard.Err = tt.copyErr
+			h.load(sampleCommits())
+			for _, repo := range tt.selected {
+				h.m.selected[repo] = true
+			}
+			h.m.screen = screenSummary
+
+			h.press("Q")
+			if h.quit != tt.wantQuit {
+				t.Errorf("quit = %v, want %v", h.quit, tt.wantQuit)
+			}
+			if h.m.message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", h.m.message, tt.wantMessage)
+			}
+			// The TUI is gone once it quits, so the outcome is printed afterwards.
+			if got := h.m.ExitMessage(); got != tt.wantExit {
+				t.Errorf("exit message = %q, want %q", got, tt.wantExit)
+			}
+			copies := h.clipboard.Copies()
+			if !tt.wantCopy {
+				if len(copies) != 0 {
+					t.Errorf("copied %q, want nothing", copies)
+				}
+				return
+			}
+			if len(copies) != 1 || !strings.Contains(copies[0], "Add login") || strings.Contains(copies[0], "Style header") {
+				t.Errorf("copied %q, want the text summary of acme/api", copies)
+			}
+		})
+	}
+}
+
+func TestCopyReportsResultByMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		screen      screenState
+		copyErr     error
+		wantMessage string
+	}{
+		{name: "summary", screen: screenSummary, wantMessage: "Copied to clipboard!"},
+		{name: "summary failure", screen: screenSummary, copyErr: errors.New("no clipboard"), wantMessage: "Failed to copy: no clipboard"},
+		{name: "export", screen: screenExport, wantMessage: "Copied to clipboard!"},
+		{name: "export failure", screen: screenExport, copyErr: errors.New("no clipboard"), wantMessage: "Failed to copy: no clipboard"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, nil)
+			h.clipboard.Err = tt.copyErr
+			h.load(sampleCommits())
+			h.m.selected["acme/api"] = true
+			h.m.screen = tt.screen
+
+			// The copy runs in the returned command, not in Update.
+			_, cmd := h.m.Update(keyMsg("c"))
+			if cmd == nil {
+				t.Fatal("copy returned no command")
+			}
+			if len(h.clipboard.Copies()) != 0 || h.m.message != "" {
+				t.Fatalf("copied %q with message %q before the command ran", h.clipboard.Copies(), h.m.message)
+			}
+
+			msg, ok := cmd().(clipboardResultMsg)
+			if !ok {
+				t.Fatalf("command returned %T, want clipboardResultMsg", msg)
+			}
+			if !errors.Is(msg.err, tt.copyErr) {
+				t.Errorf("err = %v, want %v", msg.err, tt.copyErr)
+			}
+			h.send(msg)
+			if h.m.message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", h.m.message, tt.wantMessage)
+			}
+			wantCount := 1
+			if tt.copyErr != nil {
+				wantCount = 0
+			}
+			if h.m.copyCount != wantCount {
+				t.Errorf("copyCount = %d, want %d", h.m.copyCount, wantCount)
+			}
+		})
+	}
+}
+
+func TestMessageLifecycle(t *testing.T) {
+	h := newHarness(t, nil)
+	h.load(sampleCommits())
+	h.m.screen = screenSummary
+
+	h.press("Y")
+	const first = "No repositories selected"
+	if h.m.message != first {
+		t.Fatalf("message = %q, want %q", h.m.message, first)
+	}
+	firstID := h.m.messageID
+
+	// Other keys no longer clear the message.
+	h.press("+", "-")
+	if h.m.message != first {
+		t.Fatalf("message = %q after unrelated keys, want %q kept", h.m.message, first)
+	}
+
+	// A newer message replaces it and outlives the older expiry.
+	h.m.selected["acme/api"] = true
+	h.m.selected["acme/web"] = true
+	h.press("Y")
+	const second = "Copied 2 repository names!"
+	if h.m.message != second {
+		t.Fatalf("message = %q, want %q", h.m.message, second)
+	}
+	h.send(messageExpiredMsg{id: firstID})
+	if h.m.message != second {
+		t.Fatalf("message = %q after the first message expired, want %q kept", h.m.message, second)
+	}
+
+	h.send(messageExpiredMsg{id: h.m.messageID})
+	if h.m.message != "" {
+		t.Errorf("message = %q after it expired, want cleared", h.m.message)
+	}
+}
+
+func TestRepeatedMessageRestartsExpiry(t *testing.T) {
+	h := newHarness(t, nil)
+	h.load(sampleCommits())
+	h.m.selected["acme/api"] = true
+	h.m.screen = screenSummary
+
+	h.press("Y")
+	firstID := h.m.messageID
+
+	// Copying again shows the same text, which must get its own expiry.
+	h.press("Y")
+	const want = "Copied 1 repository name!"
+	if h.m.messageID == firstID {
+		t.Fatalf("messageID = %d after repeating %q, want a new one", h.m.messageID, want)
+	}
+	h.send(messageExpiredMsg{id: firstID})
+	if h.m.message != want {
+		t.Errorf("message = %q after the first expiry, want %q kept", h.m.message, want)
+	}
+}
+
+func TestMessageSchedulesExpiry(t *testing.T) {
+	h := newHarness(t, nil)
+	h.load(sampleCommits())
+	h.m.screen = screenSummary
+
+	_, cmd := h.m.Update(keyMsg("Y"))
+	if cmd == nil {
+		t.Fatal("setting a message returned no command, want an expiry tick")
+	}
+	// Unchanged messages do not schedule another expiry.
+	if _, cmd := h.m.Update(keyMsg("j")); cmd != nil {
+		t.Errorf("unrelated key returned a command %T, want none", cmd)
+	}
+}
+
+func TestCappedRangeRecovery(t *testing.T) {
+	const hint = "B re-fetch in smaller ranges"
+	h := newHarness(t, sampleCommits())
+	h.choosePreset("week")
+	h.press("enter")
+	h.wantScreen(screenRepoList)
+
+	// An uncapped range offers no recovery.
+	fetches := h.github.FetchCount()
+	h.press("B")
+	if h.github.FetchCount() != fetches {
+		t.Error("B re-fetched an uncapped range")
+	}
+
+	h.m.loading = true
+	h.m.screen = screenLoading
+	data := testutil.NewCommitData(sampleCommits())
+	h.send(commitsLoadedMsg{
+		commits:   data.Commits,
+		repoList:  data.RepoList,
+		warning:   "Only the first 1000 results were fetched",
+		fetchedAt: time.Now(),
+		capped:    true,
+	})
+	if !h.m.canRecoverCap() {
+		t.Fatal("canRecoverCap() = false for a capped week")
+	}
+	if view := h.m.View(); !strings.Contains(view, hint) {
+		t.Errorf("capped view does not offer recovery:\n%s", view)
+	}
+
+	// B splits the range: every day still hits the cap, so each is fetched.
+	h.github.Capped = true
+	h.cache.Clear()
+	start, end := h.m.startDate, h.m.endDate
+	fetches = h.github.FetchCount()
+	h.press("B")
+	h.wantScreen(screenRepoList)
+	if got := h.github.FetchCount() - fetches; got <= entity.RangeDays(start, end) {
+		t.Errorf("B made %d fetches, want more than one per day", got)
+	}
+	if h.m.startDate != start || h.m.endDate != end {
+		t.Errorf("B changed the range to %s..%s", h.m.startDate, h.m.endDate)
+	}
+
+	// D narrows to the last day, which cannot be split further. That day was
+	// cached by the bisection and is still capped.
+	h.press("D")
+	h.wantScreen(screenRepoList)
+	if h.m.startDate != end || h.m.endDate != end {
+		t.Errorf("D range = %s..%s, want %s..%s", h.m.startDate, h.m.endDate, end, end)
+	}
+	if !h.m.capped {
+		t.Error("capped = false after narrowing to a capped day")
+	}
+	if h.m.canRecoverCap() {
+		t.Error("canRecoverCap() = true for a single day")
+	}
+	if view := h.m.View(); strings.Contains(view, hint) {
+		t.Errorf("single-day view offers recovery:\n%s", view)
+	}
+}
+
+// filter applies pattern from the repo list through the filter screen.
+func (h *harness) filter(pattern string) {
+	h.t.Helper()
+	h.press("/")
+	h.typeText(pattern)
+	h.press("enter")
+	h.wantScreen(screenRepoList)
+}
+
+func TestClearFilterKey(t *testing.T) {
+	h := newHarness(t, sampleCommits())
+	h.load(sampleCommits())
+	all := len(h.m.getDisplayRepos())
+
+	h.filter("acme/*")
+	filtered := h.m.getDisplayRepos()
+	if len(filtered) != 2 {
+		t.Fatalf("filtered = %v, want the two acme repos", filtered)
+	}
+	h.press("down")
+	current := filtered[1]
+
+	h.press("esc")
+	h.wantScreen(screenRepoList)
+	if h.m.filterActive || h.m.filterInput.Value() != "" {
+		t.Errorf("filter still set: active %v, pattern %q", h.m.filterActive, h.m.filterInput.Value())
+	}
+	if got := h.m.getDisplayRepos(); len(got) != all {
+		t.Errorf("display = %v, want all %d repos back", got, all)
+	}
+	if got := h.m.getDisplayRepos()[h.m.cursor]; got != current {
+		t.Errorf("cursor on %s, want it kept on %s", got, current)
+	}
+	if h.m.message != "Filter cleared" {
+		t.Errorf("message = %q, want %q", h.m.message, "Filter cleared")
+	}
+
+	// Without a filter esc does nothing.
+	h.m.message = ""
+	h.press("esc")
+	h.wantScreen(screenRepoList)
+	if h.m.message != "" {
+		t.Errorf("message = %q without a filter, want none", h.m.message)
+	}
+}
+
+func TestCursorAfterShrinkingFilter(t *testing.T) {
+	tests := []struct {
+		name  string
+		shown []string
+		key   string
+	}{
+		{name: "toggle", shown: []string{"acme/web"}, key: " "},
+		{name: "favorite", shown: []string{"acme/web"}, key: "*"},
+		{name: "collapse", shown: []string{"acme/web"}, key: "z"},
+		{name: "toggle on an empty list", shown: []string{}, key: " "},
+		{name: "move on an empty list", shown: []string{}, key: "down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, sampleCommits())
+			h.load(sampleCommits())
+			h.press("down", "down")
+			h.wantCursor(2)
+
+			// Shrink the list behind the cursor, as a live filter would.
+			h.m.filterActive = true
+			h.m.filteredRepos = tt.shown
+			h.press(tt.key)
+
+			h.wantScreen(screenRepoList)
+			h.wantCursor(0)
+			if got := h.selectedRepos(); tt.key == " " && !slices.Equal(got, tt.shown) {
+				t.Errorf("selected = %v, want %v", got, tt.shown)
+			}
+		})
+	}
+}
+
+func TestEmptyFilterInteractions(t *testing.T) {
+	const nudge = "No repos match the filter; press esc to clear it"
+	h := newHarness(t, sampleCommits())
+	h.load(sampleCommits())
+	h.filter("nomatch/*")
+
+	if got := h.m.getDisplayRepos(); len(got) != 0 {
+		t.Fatalf("display = %v, want none", got)
+	}
+	if view := h.m.View(); !strings.Contains(view, "No repos match filter nomatch/*") {
+		t.Errorf("view lacks the empty filter notice:\n%s", view)
+	}
+
+	for _, key := range []string{"enter", "s", " ", "down", "up"} {
+		h.m.message = ""
+		h.press(key)
+		h.wantScreen(screenRepoList)
+		h.wantCursor(0)
+		if got := h.selectedRepos(); len(got) != 0 {
+			t.Errorf("%q selected %v", key, got)
+		}
+		if wantNudge := key == "enter" || key == "s"; wantNudge != (h.m.message == nudge) {
+			t.Errorf("%q: message = %q, want nudge %v", key, h.m.message, wantNudge)
+		}
+	}
+
+	h.press("esc")
+	if got := h.m.getDisplayRepos(); len(got) != 3 {
+		t.Errorf("display after esc = %v, want all repos", got)
+	}
+}
+
+func TestCopySelectedRepoNames(t *testing.T) {
+	tests := []struct {
+		name        string
+		selected    []string
+		wantCopy    string
+		wantMessage string
+	}{
+		{
+			name:        "sorted names",
+			selected:    []string{"octocat/dotfiles", "acme/api"},
+			wantCopy:    "acme/api\noctocat/dotfiles",
+			wantMessage: "Copied 2 repository names!",
+		},
+		{
+			name:        "single repo",
+			selected:    []string{"acme/web"},
+			wantCopy:    "acme/web",
+			wantMessage: "Copied 1 repository name!",
+		},
+		{
+			name:        "empty selection",
+			wantMessage: "No repositories selected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, nil)
+			h.load(sampleCommits())
+			for _, repo := range tt.selected {
+				h.m.selected[repo] = true
+			}
+			h.m.screen = screenSummary
+
+			h.press("Y")
+			if h.m.message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", h.m.message, tt.wantMessage)
+			}
+			copies := h.clipboard.Copies()
+			if tt.wantCopy == "" {
+				if len(copies) != 0 {
+					t.Errorf("copied %q, want nothing", copies)
+				}
+				return
+			}
+			if got := h.clipboard.Last(); got != tt.wantCopy {
+				t.Errorf("copied %q, want %q", got, tt.wantCopy)
+			}
+		})
+	}
+}
+
+func TestAuthErrorLogin(t *testing.T) {
+	authErr := fmt.Errorf("%w: gh is installed but not logged in", repository.ErrNotAuthenticated)
+
+	tests := []struct {
+		name      string
+		err       error
+		withLogin bool
+		wantLogin bool
+	}{
+		{name: "auth error offers login", err: authErr, withLogin: true, wantLogin: true},
+		{name: "no login command", err: authErr, withLogin: false, wantLogin: false},
+		{name: "gh not installed", err: fmt.Errorf("%w: install it from https://cli.github.com/", repository.ErrGHNotInstalled), withLogin: true, wantLogin: false},
+		{name: "other errors", err: errors.New("network down"), withLogin: true, wantLogin: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, sampleCommits())
+			logins := 0
+			if tt.withLogin {
+				h.m.SetLoginCommand(func() *exec.Cmd {
+					logins++
+					return exec.Command("true")
+				})
+			}
+			h.github.UserErr = tt.err
+			h.choosePreset("today")
+			h.press("enter")
+			h.wantScreen(screenRepoList)
+			if !errors.Is(h.m.err, tt.err) {
+				t.Fatalf("err = %v, want %v", h.m.err, tt.err)
+			}
+
+			view := h.m.View()
+			if got := strings.Contains(view, "Press l to run gh auth login"); got != tt.wantLogin {
+				t.Errorf("login hint shown = %v, want %v:\n%s", got, tt.wantLogin, view)
+			}
+
+			// The command suspends the TUI, so inspect it instead of running it.
+			_, cmd := h.m.Update(keyMsg("l"))
+			if got := cmd != nil; got != tt.wantLogin {
+				t.Fatalf("login command returned = %v, want %v", got, tt.wantLogin)
+			}
+			if tt.wantLogin && logins != 1 {
+				t.Errorf("login command built %d times, want 1", logins)
+			}
+		})
+	}
+}
+
+func TestAuthLoginDone(t *testing.T) {
+	tests := []struct {
+		name        string
+		loginErr    error
+		wantScreen  screenState
+		wantErr     bool
+		wantMessage string
+	}{
+		{name: "login succeeded", wantScreen: screenRepoList},
+		{
+			name:        "login failed",
+			loginErr:    errors.New("exit status 1"),
+			wantScreen:  screenRepoList,
+			wantErr:     true,
+			wantMessage: "gh auth login did not complete: exit status 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, sampleCommits())
+			h.github.UserErr = repository.ErrNotAuthenticated
+			h.choosePreset("today")
+			h.press("enter")
+			fetches := h.github.FetchCount()
+
+			// Logging in fixes the account; a failed login leaves it broken.
+			if tt.loginErr == nil {
+				h.github.UserErr = nil
+			}
+			h.send(authLoginDoneMsg{err: tt.loginErr})
+
+			h.wantScreen(tt.wantScreen)
+			if got := h.m.err != nil; got != tt.wantErr {
+				t.Errorf("err = %v, want error %v", h.m.err, tt.wantErr)
+			}
+			if h.m.message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", h.m.message, tt.wantMessage)
+			}
+			if tt.loginErr == nil {
+				if h.github.FetchCount() == fetches {
+					t.Error("fetch not retried after login")
+				}
+				if len(h.m.getDisplayRepos()) != len(sampleCommits()) {
+					t.Errorf("displayed %v after login", h.m.getDisplayRepos())
+				}
+			}
+		})
+	}
+}
+
+func TestApplyProfileMessageMasksRepos(t *testing.T) {
+	h := newHarness(t, nil, func(cfg *config.Config) {
+		cfg.SelectionProfiles = map[string][]string{"standup": {"acme/api", "acme/docs"}}
+	})
+	h.load(sampleCommits())
+	h.m.privacyMode = true
+
+	h.press("p")
+	h.typeText("standup")
+	h.press("enter")
+	h.wantScreen(screenRepoList)
+	if want := "no commits in range: " + maskRepoName("acme/docs"); !strings.Contains(h.m.message, want) {
+		t.Errorf("message = %q, want it to contain %q", h.m.message, want)
+	}
+	if strings.Contains(h.m.message, "acme/docs") {
+		t.Errorf("message = %q shows the raw repo name in privacy mode", h.m.message)
+	}
+}
@@ -2,10 +2,21 @@ package ui
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
 
 	"github.com/DementevVV/commitsum/internal/domain/entity"
+	"github.com/DementevVV/commitsum/internal/infrastructure/config"
+	"github.com/DementevVV/commitsum/internal/infrastructure/logger"
 )
 
+// estimatedLineBytes is a rough size of one styled line, used to preallocate
+// builders for long lists.
+const estimatedLineBytes = 96
+
 // View renders the current state of the application model.
 func (m *Model) View() string {
 	switch m.screen {
@@ -25,6 +36,20 @@ func (m *Model) View() string {
 		return m.viewStats()
 	case screenLoading:
 		return m.viewLoading()
+	case screenCompareInput:
+		return m.viewCompareInput()
+	case screenCompareRangeInput:
+		return m.viewCompareRangeInput()
+	case screenCompare:
+		return m.viewCompare()
+	case screenProfile:
+		return m.viewProfile()
+	case screenAbout:
+		return m.viewAbout()
+	case screenWizard:
+		return m.viewWizard()
+	case screenConfirmRange:
+		return m.viewConfirmRange()
 	}
 
 	return ""
@@ -42,20 +67,23 @@ func (m *Model) viewDateRange() string {
 
 		label := preset.Label
 		if preset.Key != "custom" {
-			dr := entity.GetDateRange(preset.Key)
-			label += " " + styleFooter.Render("("+entity.FormatDateDisplay(dr.StartDate, dr.EndDate)+")")
+			dr := m.presetRange(preset.Key)
+			label += " " + styleFooter.Render("("+formatDateRange(dr.StartDate, dr.EndDate)+")")
 		}
 
 		s += cursor + styleRepo.Render(label) + "\n"
 	}
 
-	s += renderHelpBar([][]string{
+	s += m.rateLimitLine()
+
+	s += m.renderHelpBar([][]string{
 		{"j/k", "navigate"},
 		{"enter", "select"},
+		{"?", "about"},
 		{"q", "quit"},
 	})
 
-	return "\n" + styleBox.Render(s) + "\n"
+	return renderBox(s)
 }
 
 func (m *Model) viewDateSelect() string {
@@ -68,15 +96,21 @@ func (m *Model) viewDateSelect() string {
 	s += styleDateLabel.Render("Enter custom date:") + "\n\n"
 
 	inputBox := styleInputBox.Render(m.dateInput.View())
+	if m.dateInputSelected {
+		inputBox = styleInputBox.Render(styleInputSelected.Render(m.dateInput.Value()))
+	}
 
 	s += inputBox + "\n\n"
+	if m.dateLimitHit {
+		s += renderWarningBanner(fmt.Sprintf("Dates are %d characters; extra input was ignored", dateInputLimit)) + "\n\n"
+	}
 	s += styleFooter.Render("Format: YYYY-MM-DD (e.g., 2026-02-02)") + "\n"
-	s += renderHelpBar([][]string{
+	s += m.renderHelpBar([][]string{
 		{"enter", "confirm"},
 		{"esc", "back"},
 	})
 
-	return "\n" + styleBox.Render(s) + "\n"
+	return renderBox(s)
 }
 
 func (m *Model) viewRepoFilter() string {
@@ -87,50 +121,108 @@ func (m *Model) viewRepoFilter() string {
 
 	s += inputBox + "\n\n"
 	s += styleFooter.Render("Use * as wildcard (e.g., *project* or org/*)") + "\n"
-	s += renderHelpBar([][]string{
+	s += m.renderHelpBar([][]string{
 		{"enter", "apply"},
 		{"esc", "cancel"},
 	})
 
-	return "\n" + styleBox.Render(s) + "\n"
+	return renderBox(s)
 }
 
 func (m *Model) viewRepoList() string {
 	repos := m.getDisplayRepos()
 
+	if m.isAuthError() {
+		s := renderHeader("Authentication Required")
+		s += renderErrorBanner(m.err.Error()) + "\n"
+		if m.canLogin() {
+			s += styleFooter.Render("Press l to run gh auth login; the fetch is retried afterwards.") + "\n"
+		}
+		if m.message != "" {
+			s += renderWarningBanner(m.message) + "\n"
+		}
+		s += m.renderHelpBar([][]string{
+			helpItem("l", "gh auth login", m.canLogin()),
+			{"r", "change date"},
+			{"q", "quit"},
+		})
+		return renderBox(s)
+	}
+
 	if m.err != nil {
 		s := renderHeader("Error")
 		s += renderErrorBanner(m.err.Error()) + "\n"
-		s += renderHelpBar([][]string{{"r", "retry"}, {"q", "quit"}})
-		return "\n" + styleBox.Render(s) + "\n"
+		s += m.renderHelpBar([][]string{{"r", "retry"}, {"q", "quit"}})
+		return renderBox(s)
+	}
+
+	if len(repos) == 0 && m.filterActive {
+		s := renderHeader("No Matches")
+		s += styleFooter.Render("No repos match filter "+m.filterInput.Value()) + "\n"
+		if m.message != "" {
+			s += "\n" + renderWarningBanner(m.message) + "\n"
+		}
+		s += m.renderHelpBar([][]string{{"esc", "clear filter"}, {"f", "edit filter"}, {"r", "change date"}, {"q", "quit"}})
+		return renderBox(s)
+	}
+
+	if len(repos) == 0 && m.favoritesOnly {
+		s := renderHeader("No Favorites")
+		s += styleFooter.Render("None of your favorite repositories have commits in this range.") + "\n"
+		s += m.renderHelpBar([][]string{{"F", "show all"}, {"r", "change date"}, {"q", "quit"}})
+		return renderBox(s)
 	}
 
 	if len(repos) == 0 {
-		dateStr := entity.FormatDateDisplay(m.startDate, m.endDate)
+		dateStr := formatDateRange(m.startDate, m.endDate)
 		s := renderHeader("No Commits Found")
 		s += styleFooter.Render("No commits found for "+dateStr) + "\n"
-		s += renderHelpBar([][]string{{"r", "change date"}, {"q", "quit"}})
-		return "\n" + styleBox.Render(s) + "\n"
+		s += m.renderHelpBar([][]string{{"r", "change date"}, {"q", "quit"}})
+		return renderBox(s)
 	}
 
-	dateDisplay := entity.FormatDateDisplay(m.startDate, m.endDate)
-	s := renderHeader("Commits for " + dateDisplay)
-
 	// List header with count.
 	totalCommits := 0
+	expandedCommits := 0
+	selectedCount := 0
+	expanded := m.expandedRepos(repos)
 	for _, repo := range repos {
 		totalCommits += len(m.commits[repo])
+		if m.selected[repo] {
+			selectedCount++
+		}
+		if expanded[repo] {
+			expandedCommits += len(m.commits[repo])
+		}
+	}
+
+	// Preallocate roughly one styled line per repo and expanded commit.
+	var b strings.Builder
+	b.Grow((len(repos) + expandedCommits + 16) * estimatedLineBytes)
+
+	dateDisplay := formatDateRange(m.startDate, m.endDate)
+	if m.canExcludeToday() && m.rangeLabel != "" {
+		dateDisplay = m.rangeLabel + " (" + dateDisplay + ")"
 	}
-	s += renderListHeader("Repositories", len(repos)) + "  " +
-		styleFooter.Render(fmt.Sprintf("• %d commits total", totalCommits)) + "\n"
-	s += renderDivider(50) + "\n\n"
+	b.WriteString(renderHeader("Commits for " + dateDisplay))
+	b.WriteString(renderListHeader("Repositories", len(repos)) + "  " +
+		styleFooter.Render(fmt.Sprintf("%s %d commits total", iconSeparator, totalCommits)) +
+		styleTag.Render("  by "+m.commitUC.DateField().Label()+" "+iconSeparator+" sorted by "+m.repoSort.Label()) + m.dataAgeTag() + m.favoritesTag() + m.privacyTag() + "\n")
+	b.WriteString(renderDivider(50) + "\n\n")
 
 	// Show filter if active.
 	if m.filterActive && m.filterInput.Value() != "" {
-		s += styleFooter.Render("Filter: "+m.filterInput.Value()) + "\n\n"
+		b.WriteString(styleFooter.Render("Filter: "+m.filterInput.Value()) + "\n\n")
+	}
+	if hiddenCommits, hiddenRepos := m.hiddenCounts(); hiddenCommits > 0 || hiddenRepos > 0 {
+		b.WriteString(styleTag.Render(fmt.Sprintf("Hiding %d commits / %d repos (filters active)", hiddenCommits, hiddenRepos)) + "\n\n")
 	}
 	if m.warning != "" {
-		s += renderWarningBanner(m.warning) + "\n\n"
+		b.WriteString(renderWarningBanner(m.warning) + "\n")
+		if m.canRecoverCap() {
+			b.WriteString(styleTag.Render("B re-fetch in smaller ranges "+iconSeparator+" D narrow to "+formatDateRange(m.endDate, m.endDate)) + "\n")
+		}
+		b.WriteString("\n")
 	}
 
 	for i, repo := range repos {
@@ -140,28 +232,89 @@ func (m *Model) viewRepoList() string {
 		}
 
 		commitCount := styleFooter.Render(fmt.Sprintf(" (%d)", len(m.commits[repo])))
+		if m.showPercent && totalCommits > 0 {
+			commitCount += styleFooter.Render(fmt.Sprintf(" (%2d%%)", len(m.commits[repo])*100/totalCommits))
+		}
+		if gain := m.gained[repo]; gain > 0 {
+			commitCount += styleGainBadge.Render(fmt.Sprintf(" +%d", gain))
+		}
 
 		if i == m.cursor {
-			s += styleCursor.Render(iconArrowRight) + checkbox + " " + styleRepo.Render(repo) + commitCount + "\n"
+			b.WriteString(styleCursor.Render(iconArrowRight))
 		} else {
-			s += "  " + checkbox + " " + styleRepo.Render(repo) + commitCount + "\n"
+			b.WriteString("  ")
+		}
+		star := ""
+		if m.isFavorite(repo) {
+			star = styleFavorite.Render(iconFavorite) + " "
+		}
+		if m.selected[repo] && m.isOwnerCollapsed(repo) {
+			commitCount += styleTag.Render(" (owner collapsed)")
 		}
+		b.WriteString(checkbox + " " + star + styleRepo.Render(m.repoLabel(repo)) + commitCount + "\n")
 
-		if m.selected[repo] {
+		if expanded[repo] {
 			for _, commit := range m.commits[repo] {
-				s += "     " + styleHighlight.Render(iconCommit) + " " + styleCommit.Render(commit.Message) + "\n"
+				b.WriteString("     ")
+				b.WriteString(renderCommitLine(commit, m.bullet, entity.MessageCase(m.config.MessageCase), false, m.isNewCommit(commit)))
+				b.WriteString("\n")
 			}
 		}
 	}
 
-	s += renderHelpBar([][]string{
-		{"space", "select"},
-		{"a/n", "all/none"},
+	ownerCollapsed := 0
+	for _, repo := range repos {
+		if m.selected[repo] && m.isOwnerCollapsed(repo) {
+			ownerCollapsed++
+		}
+	}
+	if collapsed := selectedCount - ownerCollapsed - len(expanded); collapsed > 0 {
+		b.WriteString("\n" + styleTag.Render(fmt.Sprintf("%d more selected repos collapsed %s see the summary or press x to expand all", collapsed, iconSeparator)) + "\n")
+	}
+	if m.message != "" {
+		b.WriteString("\n" + renderSuccessBanner(m.message) + "\n")
+	}
+
+	hasRepos := len(repos) > 0
+	help := [][]string{
+		helpItem("space", "select", hasRepos),
+		helpItem("1-0", "quick pick", hasRepos),
+		helpItem("a/n", "all/none", hasRepos),
 		{"f", "filter"},
-		{"enter", "summary"},
+	}
+	if m.filterActive {
+		help = append(help, []string{"esc", "clear filter"})
+	}
+	help = append(help, [][]string{
+		{"o", "sort"},
+		{"t", "date field"},
+		{"%", "percent"},
+		{"*", "favorite"},
+		{"F", "favorites only"},
+		{"p", "profiles"},
+		{"z/Z/E", "collapse owner/all, expand all"},
+	}...)
+	if m.config.MaxExpandedRepos > 0 {
+		expandHint := "expand all"
+		if m.expandAll {
+			expandHint = "collapse"
+		}
+		help = append(help, []string{"x", expandHint})
+	}
+	if m.canExcludeToday() {
+		todayHint := "exclude today"
+		if m.config.ExcludeToday {
+			todayHint = "include today"
+		}
+		help = append(help, []string{"T", todayHint})
+	}
+	help = append(help, [][]string{
+		{"v", "compare"},
+		helpItem("enter", "summary", m.hasSelection()),
 		{"q", "quit"},
-	})
-	return "\n" + styleBox.Render(s) + "\n"
+	}...)
+	b.WriteString(m.renderHelpBar(help))
+	return renderBox(b.String())
 }
 
 func (m *Model) viewExport() string {
@@ -175,6 +328,12 @@ func (m *Model) viewExport() string {
 		{"Text", "Plain text format (.txt)"},
 		{"Markdown", "Markdown format (.md)"},
 		{"JSON", "JSON format (.json)"},
+		{"Heatmap", "Commits by weekday and hour (.csv)"},
+		{"PR body", "Pull request description for gh pr create --body-file"},
+		{"GitHub tasks", "Task list to paste into an issue (- [ ] per commit)"},
+		{"Daily", "Markdown report with commits grouped under each day"},
+		{"Compact", "One line: repo: count, busiest first (for chat)"},
+		{"Legacy JSON", "Compatibility: old {items: [...]} API response shape"},
 	}
 
 	for i, f := range formats {
@@ -185,31 +344,61 @@ func (m *Model) viewExport() string {
 		s += cursor + styleRepo.Render(f.name) + " " + styleFooter.Render(f.desc) + "\n"
 	}
 
+	if m.filterActive {
+		scope := "all selected repositories"
+		if m.exportFilteredOnly {
+			scope = "selected repositories matching " + m.filterInput.Value()
+		}
+		s += "\n" + styleStatsLabel.Render("Scope: ") + styleHighlight.Render(scope) + "\n"
+	}
+
 	if m.message != "" {
 		s += "\n" + renderSuccessBanner(m.message) + "\n"
 	}
+	if m.exportWarning != "" {
+		s += "\n" + renderWarningBanner(m.exportWarning) + "\n"
+	}
 
-	s += renderHelpBar([][]string{
-		{"enter", "save file"},
-		{"c", "copy"},
-		{"b", "back"},
-	})
+	canExport := len(m.commitUC.GetSelectedReposSorted(m.summaryCommits(), m.exportSelection())) > 0
+	help := [][]string{
+		helpItem("enter", "save file", canExport),
+		helpItem("c", "copy", canExport),
+	}
+	if m.filterActive {
+		help = append(help, []string{"f", "filter scope"})
+	}
+	help = append(help, []string{"b", "back"})
+	s += m.renderHelpBar(help)
 
-	return "\n" + styleBox.Render(s) + "\n"
+	return renderBox(s)
 }
 
 func (m *Model) viewLoading() string {
-	dateStr := entity.FormatDateDisplay(m.startDate, m.endDate)
+	dateStr := formatDateRange(m.startDate, m.endDate)
 
 	s := renderHeader("Loading")
 	s += m.spinner.View() + " " + styleDateLabel.Render("Fetching commits for "+dateStr+"...") + "\n\n"
+
+	if len(m.placeholderRepos) > 0 {
+		// Skeleton of the previous results for this range.
+		const maxSkeletonRows = 10
+		for i, repo := range m.placeholderRepos {
+			if i == maxSkeletonRows {
+				s += styleSkeleton.Render(fmt.Sprintf("    … %d more", len(m.placeholderRepos)-maxSkeletonRows)) + "\n"
+				break
+			}
+			s += "  " + styleSkeleton.Render(iconUncheckBox+" "+m.repoLabel(repo)) + "\n"
+		}
+	}
+
 	s += styleFooter.Render("Connecting to GitHub API") + "\n"
-	s += renderHelpBar([][]string{
+	s += m.rateLimitLine()
+	s += m.renderHelpBar([][]string{
 		{"esc", "cancel"},
 		{"q", "quit"},
 	})
 
-	return "\n" + styleBox.Render(s) + "\n"
+	return renderBox(s)
 }
 
 func (m *Model) viewStats() string {
@@ -217,21 +406,28 @@ func (m *Model) viewStats() string {
 
 	if m.stats == nil {
 		s += styleFooter.Render("No statistics available") + "\n"
-		s += renderHelpBar([][]string{
+		if m.message != "" {
+			s += renderSuccessBanner(m.message) + "\n"
+		}
+		s += m.renderHelpBar([][]string{
 			{"b", "back"},
 			{"q", "quit"},
 		})
-		return "\n" + styleBox.Render(s) + "\n"
+		return renderBox(s)
 	}
 
 	stats := m.stats
 
 	// Summary stats with nice formatting.
-	s += styleStatsLabel.Render("Total Commits:      ") + styleStatsValue.Render(fmt.Sprintf("%d", stats.TotalCommits)) + "\n"
+	s += styleStatsLabel.Render("Total Commits:      ") + styleStatsValue.Render(fmt.Sprintf("%d", stats.TotalCommits))
+	if stats.Reverts > 0 {
+		s += styleFooter.Render(fmt.Sprintf(", %d reverts", stats.Reverts))
+	}
+	s += "\n"
 	s += styleStatsLabel.Render("Total Repositories: ") + styleStatsValue.Render(fmt.Sprintf("%d", stats.TotalRepositories)) + "\n"
 
 	if stats.MostActiveRepo != "" {
-		s += styleStatsLabel.Render("Most Active:        ") + styleStatsValue.Render(stats.MostActiveRepo) +
+		s += styleStatsLabel.Render("Most Active:        ") + styleStatsValue.Render(m.repoLabel(stats.MostActiveRepo)) +
 			styleFooter.Render(fmt.Sprintf(" (%d commits)", stats.MaxCommits)) + "\n"
 	}
 
@@ -242,85 +438,402 @@ func (m *Model) viewStats() string {
 	maxCommits := findMaxCommits(stats.CommitsPerRepo)
 	barWidth := 25 // Width of the progress bar.
 
-	// Find the longest repo name for alignment.
+	// Find the longest repo label for alignment.
 	maxRepoLen := 0
 	for repo := range stats.CommitsPerRepo {
-		if len(repo) > maxRepoLen {
-			maxRepoLen = len(repo)
+		if w := lipgloss.Width(m.repoLabel(repo)); w > maxRepoLen {
+			maxRepoLen = w
 		}
 	}
 
 	for repo, count := range stats.CommitsPerRepo {
-		// Pad repo name for alignment.
-		paddedRepo := repo
-		for len(paddedRepo) < maxRepoLen {
-			paddedRepo += " "
-		}
-
 		// Calculate percentage.
 		pct := 0
 		if stats.TotalCommits > 0 {
 			pct = (count * 100) / stats.TotalCommits
 		}
 
-		bar := renderProgressBar(count, maxCommits, barWidth)
+		// Text mode drops the bars so screen readers get plain numbers.
+		if m.config.StatsStyle == "text" {
+			s += fmt.Sprintf("  %s: %d (%d%%)\n", m.repoLabel(repo), count, pct)
+			continue
+		}
+
+		// Pad repo label for alignment.
+		paddedRepo := m.repoLabel(repo)
+		for lipgloss.Width(paddedRepo) < maxRepoLen {
+			paddedRepo += " "
+		}
+
+		bar := renderProgressBar(count, maxCommits, barWidth, m.config.StatsScale == "log")
 		s += "  " + styleRepo.Render(paddedRepo) + " " + bar + " " +
 			styleStatsValue.Render(fmt.Sprintf("%2d", count)) + " " +
 			styleFooter.Render(fmt.Sprintf("(%2d%%)", pct)) + "\n"
 	}
 
-	s += renderHelpBar([][]string{
+	if inactive := m.inactiveRepos(); len(inactive) > 0 {
+		s += "\n" + styleTag.Render("No activity: "+m.repoLabels(inactive)) + "\n"
+	}
+
+	if m.message != "" {
+		s += "\n" + renderSuccessBanner(m.message) + "\n"
+	}
+
+	s += m.renderHelpBar([][]string{
+		{"c", "copy JSON"},
+		{"v", "compare range"},
 		{"b", "back"},
 		{"q", "quit"},
 	})
 
-	return "\n" + styleBox.Render(s) + "\n"
+	return renderBox(s)
 }
 
 func (m *Model) viewSummary() string {
-	dateStr := entity.FormatDateDisplay(m.startDate, m.endDate)
-	s := renderHeader("Summary for " + dateStr)
+	commits := m.summaryCommits()
+	repos := m.commitUC.GetSelectedReposSorted(commits, m.summarySelection())
+
+	// Count total commits.
+	totalCommits := 0
+	for _, repo := range repos {
+		totalCommits += len(commits[repo])
+	}
+
+	// Preallocate roughly one styled line per repo and commit.
+	var b strings.Builder
+	b.Grow((2*len(repos) + totalCommits + 16) * estimatedLineBytes)
+
+	dateStr := formatDateRange(m.startDate, m.endDate)
+	b.WriteString(renderHeader("Summary for " + dateStr))
+	if m.hideMerges {
+		b.WriteString(styleTag.Render("merge commits hidden "+iconSeparator+" M to show") + "\n\n")
+	}
 
 	hasSelection := false
-	repos := m.commitUC.GetSelectedReposSorted(m.commits, m.selected)
 
 	if len(repos) > 0 {
-		// Count total commits.
-		totalCommits := 0
-		for _, repo := range repos {
-			totalCommits += len(m.commits[repo])
+		b.WriteString(renderListHeader("Selected repositories", len(repos)) + "  " +
+			styleFooter.Render(fmt.Sprintf("%s %d commits", iconSeparator, totalCommits)) + "\n")
+		b.WriteString(renderDivider(50) + "\n\n")
+	}
+
+	groups := []entity.RepoGroup{{Repos: repos}}
+	if m.config.GroupByOwnership {
+		groups = m.commitUC.GroupByOwnership(repos)
+	}
+
+	for _, group := range groups {
+		if group.Title != "" {
+			b.WriteString(renderListHeader(group.Title, len(group.Repos)) + "\n\n")
+		}
+		for _, repo := range group.Repos {
+			repoCommits := commits[repo]
+			hasSelection = true
+			b.WriteString(styleRepo.Render(iconRepo+m.repoLabel(repo)) + "\n")
+
+			prGroups := []entity.PRGroup{{Commits: repoCommits}}
+			if m.config.GroupByPR {
+				if groups := entity.GroupByPR(repoCommits); groups != nil {
+					prGroups = groups
+				}
+			}
+			for _, group := range prGroups {
+				indent := "  "
+				if group.PR != nil {
+					b.WriteString("  " + styleHighlight.Render(group.PR.Heading()) + "\n")
+					indent = "    "
+				}
+				for _, commit := range group.Commits {
+					b.WriteString(indent)
+					b.WriteString(renderCommitLine(commit, m.bullet, entity.MessageCase(m.config.MessageCase), m.config.ShowSHA, m.isNewCommit(commit)))
+					b.WriteString("\n")
+				}
+			}
+			b.WriteString("\n")
 		}
-		s += renderListHeader("Selected repositories", len(repos)) + "  " +
-			styleFooter.Render(fmt.Sprintf("• %d commits", totalCommits)) + "\n"
-		s += renderDivider(50) + "\n\n"
 	}
 
-	for _, repo := range repos {
-		repoCommits := m.commits[repo]
-		hasSelection = true
-		s += styleRepo.Render("▸ "+repo) + "\n"
+	if !hasSelection {
+		b.WriteString(styleFooter.Render("No repositories selected.") + "\n\n")
+	}
+	if inactive := m.inactiveRepos(); len(inactive) > 0 {
+		b.WriteString(styleTag.Render("No activity: "+m.repoLabels(inactive)) + "\n\n")
+	}
+	if m.minCommits > 0 {
+		b.WriteString(styleTag.Render(fmt.Sprintf("%d selected repos with fewer than %d commits omitted %s +/- to adjust",
+			m.belowMinCommits(), m.minCommits, iconSeparator)) + "\n\n")
+	}
+
+	if m.message != "" {
+		b.WriteString(renderSuccessBanner(m.message) + "\n")
+	}
+	if m.exportWarning != "" {
+		b.WriteString(renderWarningBanner(m.exportWarning) + "\n")
+	}
+
+	// Copy and export need at least one repository in the summary.
+	b.WriteString(m.renderHelpBar([][]string{
+		helpItem("c", "copy", hasSelection),
+		helpItem("Q", "copy & quit", hasSelection),
+		helpItem("Y", "copy names", hasSelection),
+		helpItem("C", "copy compact", hasSelection),
+		helpItem("e", "export", hasSelection),
+		helpItem("s", "stats", hasSelection),
+		{"M", "merges"},
+		{"+/-", "min commits"},
+		{"b", "back"},
+		{"q", "quit"},
+	}))
+
+	return renderBox(b.String())
+}
+
+func (m *Model) viewProfile() string {
+	s := renderHeader("Selection Profiles")
+
+	if m.err != nil {
+		s += renderErrorBanner(m.err.Error()) + "\n\n"
+	}
 
-		for _, commit := range repoCommits {
-			s += "  " + styleHighlight.Render(iconCommit) + " " + styleCommit.Render(commit.Message) + "\n"
+	names := slices.Sorted(maps.Keys(m.config.SelectionProfiles))
+	if len(names) == 0 {
+		s += styleFooter.Render("No profiles saved yet.") + "\n\n"
+	} else {
+		s += renderListHeader("Saved profiles", len(names)) + "\n"
+		for _, name := range names {
+			s += "  " + styleRepo.Render(name) +
+				styleFooter.Render(fmt.Sprintf(" (%d repos)", len(m.config.SelectionProfiles[name]))) + "\n"
 		}
 		s += "\n"
 	}
 
-	if !hasSelection {
-		s += styleFooter.Render("No repositories selected.") + "\n\n"
+	s += styleDateLabel.Render("Profile name:") + "\n\n"
+	s += styleInputBox.Render(m.profileInput.View()) + "\n\n"
+	s += m.renderHelpBar([][]string{
+		{"enter", "apply"},
+		{"ctrl+s", "save selection"},
+		{"esc", "back"},
+	})
+
+	return renderBox(s)
+}
+
+func (m *Model) viewCompareInput() string {
+	s := renderHeader("Compare with Teammate")
+
+	if m.err != nil {
+		s += renderErrorBanner(m.err.Error()) + "\n\n"
+	}
+
+	s += styleDateLabel.Render("Enter a teammate's GitHub username:") + "\n\n"
+	s += styleInputBox.Render(m.teammateInput.View()) + "\n\n"
+	s += styleFooter.Render("Compares commits for "+formatDateRange(m.startDate, m.endDate)) + "\n"
+	s += m.renderHelpBar([][]string{
+		{"enter", "compare"},
+		{"esc", "back"},
+	})
+
+	return renderBox(s)
+}
+
+func (m *Model) viewCompareRangeInput() string {
+	s := renderHeader("Compare Ranges")
+
+	if m.err != nil {
+		s += renderErrorBanner(m.err.Error()) + "\n\n"
+	}
+
+	s += styleDateLabel.Render("Enter a range to compare with (START..END or a single date):") + "\n\n"
+	s += styleInputBox.Render(m.compareRangeInput.View()) + "\n\n"
+	s += styleFooter.Render("Compared against "+formatDateRange(m.startDate, m.endDate)) + "\n"
+	s += m.renderHelpBar([][]string{
+		{"enter", "compare"},
+		{"esc", "back"},
+	})
+
+	return renderBox(s)
+}
+
+func (m *Model) viewCompare() string {
+	s := renderHeader("Comparison")
+
+	if m.comparison == nil {
+		s += styleFooter.Render("No comparison available") + "\n"
+		s += m.renderHelpBar([][]string{{"b", "back"}, {"q", "quit"}})
+		return renderBox(s)
+	}
+
+	c := m.comparison
+	title := c.LeftLabel + " vs " + c.RightLabel
+	if c.DateRange != "" {
+		title += " " + iconSeparator + " " + c.DateRange
+	}
+	s += styleDateLabel.Render(title) + "\n"
+	s += styleFooter.Render(fmt.Sprintf("Only in %s: %d  %s  Only in %s: %d  %s  In both: %d",
+		c.LeftLabel, len(c.OnlyLeft), iconSeparator, c.RightLabel, len(c.OnlyRight), iconSeparator, len(c.Both))) + "\n\n"
+
+	// Find the longest repo name for alignment.
+	nameWidth := len("Repository")
+	for _, repo := range c.Repos {
+		if w := lipgloss.Width(m.repoLabel(repo.Repository)); w > nameWidth {
+			nameWidth = w
+		}
 	}
+	leftWidth := max(len(c.LeftLabel), 5)
+	rightWidth := max(len(c.RightLabel), 5)
+
+	s += "  " + styleStatsLabel.Render(fmt.Sprintf("%-*s  %*s  %*s  %6s", nameWidth, "Repository", leftWidth, c.LeftLabel, rightWidth, c.RightLabel, "Δ")) + "\n"
+	s += "  " + renderDivider(nameWidth+leftWidth+rightWidth+12) + "\n"
+
+	if len(c.Repos) == 0 {
+		s += styleFooter.Render("No commits on either side.") + "\n"
+	}
+
+	for _, repo := range c.Repos {
+		label := m.repoLabel(repo.Repository)
+		s += "  " + styleRepo.Render(label+strings.Repeat(" ", nameWidth-lipgloss.Width(label))) + "  " +
+			styleStatsValue.Render(fmt.Sprintf("%*d", leftWidth, repo.Left)) + "  " +
+			styleStatsValue.Render(fmt.Sprintf("%*d", rightWidth, repo.Right)) + "  " +
+			styleTag.Render(fmt.Sprintf("%+6d", repo.Delta)) + "\n"
+	}
+
+	s += "  " + renderDivider(nameWidth+leftWidth+rightWidth+12) + "\n"
+	s += "  " + styleStatsLabel.Render(fmt.Sprintf("%-*s", nameWidth, "Total")) + "  " +
+		styleStatsValue.Render(fmt.Sprintf("%*d", leftWidth, c.LeftTotal)) + "  " +
+		styleStatsValue.Render(fmt.Sprintf("%*d", rightWidth, c.RightTotal)) + "  " +
+		styleTag.Render(fmt.Sprintf("%+6d", c.RightTotal-c.LeftTotal)) + "\n"
 
 	if m.message != "" {
-		s += renderSuccessBanner(m.message) + "\n"
+		s += "\n" + renderSuccessBanner(m.message) + "\n"
 	}
 
-	s += renderHelpBar([][]string{
-		{"c", "copy"},
-		{"e", "export"},
-		{"s", "stats"},
+	s += m.renderHelpBar([][]string{
+		{"c", "copy JSON"},
 		{"b", "back"},
 		{"q", "quit"},
 	})
 
-	return "\n" + styleBox.Render(s) + "\n"
+	return renderBox(s)
+}
+
+func (m *Model) viewAbout() string {
+	version := m.version
+	if version == "" {
+		version = "unknown"
+	}
+	buildTime := m.buildTime
+	if buildTime == "" {
+		buildTime = "unknown"
+	}
+
+	s := renderHeader("About")
+	s += styleRepo.Render("commitsum "+version) + "\n"
+	s += styleFooter.Render("Built "+buildTime) + "\n"
+	if path, err := config.Path(); err == nil {
+		s += styleFooter.Render("Config: "+path) + "\n"
+	}
+	if dir := m.commitUC.CacheDir(); dir != "" {
+		s += styleFooter.Render("Cache:  "+dir) + "\n"
+	}
+	if path := logger.Path(); path != "" {
+		s += styleFooter.Render("Log:    "+path) + "\n"
+	}
+	s += "\n" + styleTag.Render("Include the version when reporting issues.") + "\n"
+	if len(m.refreshRanges) > 0 {
+		s += "\n" + m.viewCacheRefresh()
+	}
+	if m.message != "" {
+		s += "\n" + renderSuccessBanner(m.message) + "\n"
+	}
+	if m.confirmClearCache {
+		s += "\n" + renderWarningBanner("Clear all cached commits? Everything will be refetched.") + "\n"
+		s += m.renderHelpBar([][]string{{"y", "clear"}, {"n/esc", "cancel"}})
+		return renderBox(s)
+	}
+	s += m.renderHelpBar([][]string{
+		{"d", "copy cache dir"},
+		{"c", "copy config path"},
+		{"l", "copy log path"},
+		helpItem("W", "refresh cached ranges", !m.refreshing()),
+		{"X", "clear cache"},
+		{"any key", "back"},
+		{"q", "quit"},
+	})
+
+	return renderBox(s)
+}
+
+// viewCacheRefresh lists each range of the cache refresh with its outcome.
+func (m *Model) viewCacheRefresh() string {
+	s := renderListHeader("Refreshing cached ranges", len(m.refreshRanges)) + "\n"
+	for i, r := range m.refreshRanges {
+		label := r.label + styleFooter.Render(" ("+formatDateRange(r.StartDate, r.EndDate)+")")
+		switch {
+		case i > len(m.refreshErrs):
+			s += "  " + styleFooter.Render(r.label+" (pending)") + "\n"
+		case i == len(m.refreshErrs):
+			s += "  " + styleHighlight.Render(r.label+" (fetching...)") + "\n"
+		case m.refreshErrs[i] != nil:
+			s += "  " + styleError.Render(iconError) + " " + label + " " + styleFooter.Render(m.refreshErrs[i].Error()) + "\n"
+		default:
+			s += "  " + styleSuccess.Render(iconSuccess) + " " + label + "\n"
+		}
+	}
+	return s
+}
+
+func (m *Model) viewWizard() string {
+	s := renderHeader(fmt.Sprintf("Setup (%d/%d)", m.wizardStep+1, wizardStepCount))
+
+	var question string
+	var labels []string
+	switch m.wizardStep {
+	case wizardStepDateRange:
+		question = "Which date range should be preselected?"
+		for _, preset := range entity.DateRangePresets {
+			if preset.Key != "custom" {
+				labels = append(labels, preset.Label)
+			}
+		}
+	case wizardStepOutputFormat:
+		question = "Which export format do you use most?"
+		labels = []string{"Text", "Markdown", "JSON"}
+	default:
+		question = "Copy the summary to the clipboard automatically?"
+		labels = []string{"No", "Yes"}
+	}
+
+	s += styleDateLabel.Render(question) + "\n\n"
+	for i, label := range labels {
+		cursor := "  "
+		if i == m.wizardCursor {
+			cursor = styleCursor.Render(iconArrowRight)
+		}
+		s += cursor + styleRepo.Render(label) + "\n"
+	}
+
+	if path, err := config.Path(); err == nil {
+		s += "\n" + styleTag.Render("Saved to "+path+"; edit it any time.") + "\n"
+	}
+	s += m.renderHelpBar([][]string{
+		{"j/k", "navigate"},
+		{"enter", "next"},
+		{"esc", "skip"},
+		{"q", "quit"},
+	})
+
+	return renderBox(s)
+}
+
+func (m *Model) viewConfirmRange() string {
+	s := renderHeader("Large Date Range")
+	s += styleDateLabel.Render(formatDateRange(m.startDate, m.endDate)) +
+		styleFooter.Render(fmt.Sprintf(" (%d days)", entity.RangeDays(m.startDate, m.endDate))) + "\n\n"
+	s += renderWarningBanner("This range is large and may be slow; continue?") + "\n"
+	s += m.renderHelpBar([][]string{
+		{"y", "continue"},
+		{"n/esc", "pick another range"},
+		{"q", "quit"},
+	})
+	return renderBox(s)
 }
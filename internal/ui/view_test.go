@@ -0,0 +1,286 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/DementevVV/commitsum/internal/domain/entity"
+	"github.com/DementevVV/commitsum/internal/infrastructure/config"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// benchmarkRepos is the dataset size for the view benchmarks.
+const benchmarkRepos = 500
+
+func BenchmarkViewRepoList(b *testing.B) {
+	h := newHarness(b, nil)
+	h.load(largeCommits(benchmarkRepos))
+	h.press("a")
+
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = h.m.viewRepoList()
+	}
+}
+
+func BenchmarkViewSummary(b *testing.B) {
+	h := newHarness(b, nil)
+	h.load(largeCommits(benchmarkRepos))
+	h.press("a", "enter")
+	h.wantScreen(screenSummary)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = h.m.viewSummary()
+	}
+}
+
+func BenchmarkRepoListNavigation(b *testing.B) {
+	h := newHarness(b, nil)
+	h.load(largeCommits(benchmarkRepos))
+
+	b.ReportAllocs()
+	for i := 0; b.Loop(); i++ {
+		if i%benchmarkRepos == 0 {
+			h.m.cursor = 0
+		}
+		h.m.Update(keyMsg("down"))
+		_ = h.m.View()
+	}
+}
+
+func TestAboutShowsBuildInfo(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   string
+		buildTime string
+		want      []string
+	}{
+		{name: "provided", version: "1.2.3", buildTime: "2026-01-05T10:00:00Z", want: []string{"commitsum 1.2.3", "Built 2026-01-05T10:00:00Z"}},
+		{name: "missing", want: []string{"commitsum unknown", "Built unknown"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, nil)
+			h.m.SetBuildInfo(tt.version, tt.buildTime)
+
+			h.press("?")
+			h.wantScreen(screenAbout)
+			view := h.m.View()
+			for _, want := range tt.want {
+				if !strings.Contains(view, want) {
+					t.Errorf("about view lacks %q:\n%s", want, view)
+				}
+			}
+
+			h.press("x")
+			h.wantScreen(screenDateRange)
+		})
+	}
+}
+
+func TestStatsStyle(t *testing.T) {
+	commits := sampleCommits()
+	lines := []string{"acme/api: 2 (50%)", "acme/web: 1 (25%)", "octocat/dotfiles: 1 (25%)"}
+
+	tests := []struct {
+		style    string
+		wantBars bool
+	}{
+		{style: "text", wantBars: false},
+		{style: "bar", wantBars: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			h := newHarness(t, commits, func(cfg *config.Config) { cfg.StatsStyle = tt.style })
+			h.load(commits)
+			h.press("a", "s")
+			h.wantScreen(screenStats)
+			view := h.m.View()
+
+			for _, line := range lines {
+				if got := strings.Contains(view, line); got == tt.wantBars {
+					t.Errorf("plain line %q shown = %v, want %v:\n%s", line, got, !tt.wantBars, view)
+				}
+			}
+			if got := strings.Contains(view, barEmpty); got != tt.wantBars {
+				t.Errorf("bars shown = %v, want %v:\n%s", got, tt.wantBars, view)
+			}
+			for _, pct := range []string{"50%", "25%"} {
+				if !strings.Contains(view, pct) {
+					t.Errorf("view lacks the percentage %s:\n%s", pct, view)
+				}
+			}
+		})
+	}
+}
+
+func TestCopyStatsJSON(t *testing.T) {
+	h := newHarness(t, nil)
+	h.load(sampleCommits())
+	h.press("a", "s")
+	h.wantScreen(screenStats)
+
+	h.press("c")
+	if h.m.message != "Copied statistics JSON to clipboard!" {
+		t.Errorf("message = %q", h.m.message)
+	}
+	var got entity.Statistics
+	if err := json.Unmarshal([]byte(h.clipboard.Last()), &got); err != nil {
+		t.Fatalf("copied content is not statistics JSON: %v\n%s", err, h.clipboard.Last())
+	}
+	if !reflect.DeepEqual(&got, h.m.stats) {
+		t.Errorf("copied statistics = %+v, want %+v", got, *h.m.stats)
+	}
+}
+
+func TestCopyStatsJSONWithoutStats(t *testing.T) {
+	h := newHarness(t, nil)
+	h.m.screen = screenStats
+
+	h.press("c")
+	if h.m.message != "No statistics to copy" {
+		t.Errorf("message = %q, want %q", h.m.message, "No statistics to copy")
+	}
+	if copies := h.clipboard.Copies(); len(copies) != 0 {
+		t.Errorf("copied %q, want nothing", copies)
+	}
+}
+
+func TestPlainTerminalHasNoEscapes(t *testing.T) {
+	if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		t.Skip("stdout is a terminal")
+	}
+	if p := lipgloss.ColorProfile(); p != termenv.Ascii {
+		t.Fatalf("color profile = %v without a terminal, want Ascii", p)
+	}
+
+	h := newHarness(t, sampleCommits())
+	screens := []struct {
+		name string
+		keys []string
+	}{
+		{name: "date range"},
+		{name: "repo list", keys: []string{"enter"}},
+		{name: "summary", keys: []string{"a", "enter"}},
+		{name: "stats", keys: []string{"b", "s"}},
+		{name: "export", keys: []string{"b", "enter", "e"}},
+	}
+	for _, screen := range screens {
+		h.press(screen.keys...)
+		view := h.m.View()
+		if i := strings.IndexByte(view, '\x1b'); i >= 0 {
+			t.Errorf("%s view has an escape sequence at byte %d:\n%q", screen.name, i, view)
+		}
+		for _, r := range view {
+			if r > unicode.MaxASCII {
+				t.Errorf("%s view has non-ASCII symbol %q:\n%s", screen.name, r, view)
+				break
+			}
+		}
+	}
+}
+
+func TestPlainTerminalKeepsExportSeparator(t *testing.T) {
+	if p := lipgloss.ColorProfile(); p != termenv.Ascii {
+		t.Skip("stdout has a color profile")
+	}
+
+	h := newHarness(t, nil)
+	h.load(sampleCommits())
+	h.m.startDate, h.m.endDate = "2026-02-01", "2026-02-07"
+	h.press("a")
+
+	if got, want := formatDateRange(h.m.startDate, h.m.endDate), "2026-02-01 -> 2026-02-07"; got != want {
+		t.Errorf("formatDateRange() = %q, want %q", got, want)
+	}
+	content, err := h.m.renderExportContent(entity.FormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "2026-02-01 " + entity.RangeSeparator + " 2026-02-07"; !strings.Contains(content, want) {
+		t.Errorf("export lacks %q, the terminal must not change it:\n%s", want, content)
+	}
+}
+
+func TestNoActivityUsesRepoLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		privacy bool
+		want    string
+	}{
+		{name: "alias", want: "No activity: Docs"},
+		{name: "privacy mode", privacy: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, nil, func(cfg *config.Config) {
+				cfg.WatchlistRepos = []string{"acme/docs"}
+				cfg.RepoAliases = map[string]string{"acme/docs": "Docs"}
+			})
+			h.load(sampleCommits())
+			h.m.privacyMode = tt.privacy
+			h.press("a")
+			if tt.privacy {
+				// The mask depends on the symbol set chosen by NewModel.
+				tt.want = "No activity: " + maskRepoName("acme/docs")
+			}
+
+			for _, keys := range [][]string{{"enter"}, {"s"}} {
+				h.press(keys...)
+				view := h.m.View()
+				if !strings.Contains(view, tt.want) {
+					t.Errorf("screen %v lacks %q:\n%s", h.m.screen, tt.want, view)
+				}
+				if strings.Contains(view, "acme/docs") {
+					t.Errorf("screen %v shows the raw repo name:\n%s", h.m.screen, view)
+				}
+			}
+		})
+	}
+}
+
+func TestBulletStylePerModel(t *testing.T) {
+	if p := lipgloss.ColorProfile(); p != termenv.Ascii {
+		t.Skip("stdout has a color profile")
+	}
+
+	tests := []struct {
+		name       string
+		bullet     string
+		wantView   string
+		wantExport string
+	}{
+		{name: "ASCII bullet", bullet: "*", wantView: "*", wantExport: "  * Add login"},
+		{name: "non-ASCII falls back", bullet: "→", wantView: "-", wantExport: "  - Add login"},
+		{name: "default", wantView: "-", wantExport: "  - Add login"},
+	}
+
+	// Each model gets its own bullet; none leaks into the next.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t, nil, func(cfg *config.Config) { cfg.BulletStyle = tt.bullet })
+			if h.m.bullet != tt.wantView {
+				t.Errorf("bullet = %q, want %q", h.m.bullet, tt.wantView)
+			}
+
+			h.load(sampleCommits())
+			h.press("a")
+			content, err := h.m.renderExportContent(entity.FormatText)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(content, tt.wantExport) {
+				t.Errorf("export lacks %q:\n%s", tt.wantExport, content)
+			}
+		})
+	}
+}
@@ -4,29 +4,147 @@ package usecase
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/DementevVV/commitsum/internal/domain/entity"
 	"github.com/DementevVV/commitsum/internal/domain/repository"
+	"github.com/DementevVV/commitsum/internal/infrastructure/logger"
 )
 
 // CommitUseCase handles commit-related business logic.
 type CommitUseCase struct {
-	github repository.GitHubRepository
-	cache  repository.CacheRepository
+	github   repository.GitHubRepository
+	cache    repository.CacheRepository
+	localGit repository.LocalGitRepository
+	seen     repository.SeenRepository
+
+	includeCoauthored bool
+	dateField         entity.DateField
+	ownedOrgs         []string
+	commitOrder       entity.CommitOrder
+	cacheTTL          time.Duration
+	branch            string
+	validateCache     bool
+	localRepos        []string
+	weekdays          []time.Weekday
+	earliestDate      string
+	excludeReverts    bool
+	pullRequests      bool
+
+	// user memoizes the authenticated GitHub username.
+	user string
 }
 
 // NewCommitUseCase creates a new CommitUseCase.
 func NewCommitUseCase(github repository.GitHubRepository, cache repository.CacheRepository) *CommitUseCase {
 	return &CommitUseCase{
-		github: github,
-		cache:  cache,
+		github:      github,
+		cache:       cache,
+		dateField:   entity.DateFieldCommitter,
+		commitOrder: entity.CommitOrderAPI,
+	}
+}
+
+// SetIncludeCoauthored enables merging commits where the user is only a co-author.
+func (uc *CommitUseCase) SetIncludeCoauthored(include bool) {
+	uc.includeCoauthored = include
+}
+
+// SetOwnedOrgs sets organizations whose repositories count as owned.
+func (uc *CommitUseCase) SetOwnedOrgs(orgs []string) {
+	uc.ownedOrgs = orgs
+}
+
+// SetCommitOrder sets how commits are ordered within each repository.
+func (uc *CommitUseCase) SetCommitOrder(order entity.CommitOrder) {
+	uc.commitOrder = order
+}
+
+// SetCacheTTL overrides how long fetched commits are cached. Zero restores
+// the default expiry.
+func (uc *CommitUseCase) SetCacheTTL(ttl time.Duration) {
+	uc.cacheTTL = ttl
+}
+
+// SetBranch restricts results to commits reachable from the given branch.
+// An empty branch disables the filter.
+func (uc *CommitUseCase) SetBranch(branch string) {
+	uc.branch = branch
+}
+
+// SetPullRequests enables looking up the pull request of each fetched
+// commit (one API call per commit) so summaries can group commits by PR.
+func (uc *CommitUseCase) SetPullRequests(enabled bool) {
+	uc.pullRequests = enabled
+}
+
+// SetValidateCache enables checking cached entries that include today
+// against a cheap commit count, refetching when they differ (e.g. after a
+// force-push). It costs one extra search API call per cache hit.
+func (uc *CommitUseCase) SetValidateCache(validate bool) {
+	uc.validateCache = validate
+}
+
+// SetLocalRepos merges commits from local git checkouts at the given paths
+// into the user's results, so unpushed work shows up. A nil source or empty
+// path list disables it.
+func (uc *CommitUseCase) SetLocalRepos(source repository.LocalGitRepository, paths []string) {
+	uc.localGit = source
+	uc.localRepos = paths
+}
+
+// SetWeekdays restricts results to commits made on the given weekdays.
+// Repositories left without commits are hidden. An empty list disables the
+// filter.
+func (uc *CommitUseCase) SetWeekdays(days []time.Weekday) {
+	uc.weekdays = days
+}
+
+// SetEarliestDate rejects ranges starting before date (YYYY-MM-DD), guarding
+// against accidental huge queries. An empty date disables the floor.
+func (uc *CommitUseCase) SetEarliestDate(date string) {
+	uc.earliestDate = date
+}
+
+// SetExcludeReverts leaves revert commits out of statistics counts. They are
+// still counted in Statistics.Reverts.
+func (uc *CommitUseCase) SetExcludeReverts(exclude bool) {
+	uc.excludeReverts = exclude
+}
+
+// SetSeenStore sets where viewed commits are recorded. A nil store disables
+// new-commit tracking.
+func (uc *CommitUseCase) SetSeenStore(store repository.SeenRepository) {
+	uc.seen = store
+}
+
+// SetDateField sets which commit date range queries match against.
+func (uc *CommitUseCase) SetDateField(field entity.DateField) {
+	if field != entity.DateFieldAuthor {
+		field = entity.DateFieldCommitter
 	}
+	uc.dateField = field
 }
 
-// GetCommitsForRange fetches commits for a date range.
+// DateField returns the commit date field used for range queries.
+func (uc *CommitUseCase) DateField() entity.DateField {
+	return uc.dateField
+}
+
+// ToggleDateField switches between committer-date and author-date queries.
+func (uc *CommitUseCase) ToggleDateField() entity.DateField {
+	if uc.dateField == entity.DateFieldAuthor {
+		uc.SetDateField(entity.DateFieldCommitter)
+	} else {
+		uc.SetDateField(entity.DateFieldAuthor)
+	}
+	return uc.dateField
+}
+
+// GetCommitsForRange fetches the authenticated user's commits for a date range.
 func (uc *CommitUseCase) GetCommitsForRange(startDate, endDate string) (*entity.CommitData, error) {
 	// Validate date range.
 	if err := uc.validateDateRange(startDate, endDate); err != nil {
@@ -34,38 +152,610 @@ func (uc *CommitUseCase) GetCommitsForRange(startDate, endDate string) (*entity.
 	}
 
 	// Get GitHub user.
-	ghUser, err := uc.github.GetUser()
+	ghUser, err := uc.currentUser()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get GitHub user: %w", err)
 	}
 
-	// Build date range query.
-	dateRange := startDate
-	if startDate != endDate {
-		dateRange = fmt.Sprintf("%s..%s", startDate, endDate)
+	start := time.Now()
+	data, err := uc.fetchForAuthor(ghUser, startDate, endDate)
+	if err != nil {
+		logger.LogPerformance("get_commits", time.Since(start), "cache", "error")
+		return nil, err
+	}
+	// A live fetch stamps FetchedAt after start; cached entries are older.
+	cacheResult := "miss"
+	if data.FetchedAt.Before(start) {
+		cacheResult = "hit"
+	}
+
+	// Local commits are read fresh every time; they are cheap and change often.
+	if uc.localGit != nil && len(uc.localRepos) > 0 {
+		local, err := uc.localGit.FetchCommits(uc.localRepos, startDate, endDate, uc.dateField)
+		if err != nil {
+			data.Warning = joinWarnings(data.Warning, "Local commits unavailable: "+err.Error())
+		} else {
+			mergeCommitData(data, local)
+			uc.orderCommits(data)
+		}
+	}
+
+	if len(uc.weekdays) > 0 {
+		uc.filterByWeekday(data)
+	}
+
+	commits := 0
+	for _, repoCommits := range data.Commits {
+		commits += len(repoCommits)
+	}
+	logger.LogPerformance("get_commits", time.Since(start), "cache", cacheResult,
+		"repos", len(data.RepoList), "commits", commits)
+
+	return data, nil
+}
+
+// CacheDir returns the directory holding cached commits, or "" without a cache.
+func (uc *CommitUseCase) CacheDir() string {
+	if uc.cache == nil {
+		return ""
+	}
+	return uc.cache.Dir()
+}
+
+// ClearCache removes all cached commits, so the next fetch hits GitHub.
+func (uc *CommitUseCase) ClearCache() error {
+	if uc.cache == nil {
+		return nil
+	}
+	return uc.cache.Clear()
+}
+
+// RefreshRange refetches the authenticated user's commits for a date range
+// from GitHub and replaces the cached entry, e.g. to warm the cache before
+// going offline.
+func (uc *CommitUseCase) RefreshRange(startDate, endDate string) error {
+	if err := uc.validateDateRange(startDate, endDate); err != nil {
+		return err
+	}
+	ghUser, err := uc.currentUser()
+	if err != nil {
+		return fmt.Errorf("failed to get GitHub user: %w", err)
+	}
+	_, err = uc.refetchForAuthor(ghUser, buildDateRange(startDate, endDate))
+	return err
+}
+
+// ForgetUser clears the remembered GitHub user so the next fetch looks it up
+// again, e.g. after logging in to a different account.
+func (uc *CommitUseCase) ForgetUser() {
+	uc.user = ""
+}
+
+// RateLimitStatus returns the remaining search API quota along with a
+// warning when the next uncached fetch is likely to exceed it.
+func (uc *CommitUseCase) RateLimitStatus() (*entity.RateLimit, string, error) {
+	status, err := uc.github.RateLimitStatus()
+	if err != nil {
+		return nil, "", err
 	}
 
+	// Each fetch runs one search, plus up to three more for co-authored
+	// commits (one per address of the user).
+	needed := 1
+	if uc.includeCoauthored {
+		needed += 3
+	}
+
+	var warning string
+	if status.Remaining < needed {
+		warning = fmt.Sprintf("GitHub API rate limit nearly exceeded (%d search requests left, resets at %s); uncached fetches may fail.",
+			status.Remaining, status.Reset.Format("15:04"))
+	}
+	return status, warning, nil
+}
+
+// GetCommitsBisected fetches a range like GetCommitsForRange, but when the
+// result hit GitHub's cap it splits the range in half and fetches each half
+// the same way, down to single days. Capped stays set only if some single
+// day still hit the cap.
+func (uc *CommitUseCase) GetCommitsBisected(startDate, endDate string) (*entity.CommitData, error) {
+	data, err := uc.GetCommitsForRange(startDate, endDate)
+	if err != nil || !data.Capped || startDate == endDate {
+		return data, err
+	}
+
+	start, err := time.Parse(entity.DateLayout, startDate)
+	if err != nil {
+		return nil, err
+	}
+	days := entity.RangeDays(startDate, endDate)
+	mid := start.AddDate(0, 0, days/2-1)
+	logger.Info("Range capped, splitting", "start", startDate, "end", endDate, "split", mid.Format(entity.DateLayout))
+
+	left, err := uc.GetCommitsBisected(startDate, mid.Format(entity.DateLayout))
+	if err != nil {
+		return nil, err
+	}
+	right, err := uc.GetCommitsBisected(mid.AddDate(0, 0, 1).Format(entity.DateLayout), endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &entity.CommitData{
+		Commits:           make(map[string][]entity.Commit),
+		FetchedAt:         left.FetchedAt,
+		ExcludedByBranch:  left.ExcludedByBranch + right.ExcludedByBranch,
+		ExcludedByWeekday: left.ExcludedByWeekday + right.ExcludedByWeekday,
+	}
+	mergeCommitData(merged, left)
+	mergeCommitData(merged, right)
+	if right.FetchedAt.Before(merged.FetchedAt) {
+		merged.FetchedAt = right.FetchedAt
+	}
+	uc.orderCommits(merged)
+	return merged, nil
+}
+
+// CachedRepoList returns the repository names cached for a range from a
+// previous fetch, even if expired. It never calls GitHub and returns nil
+// when the user is not yet known or nothing is cached.
+func (uc *CommitUseCase) CachedRepoList(startDate, endDate string) []string {
+	if uc.cache == nil || uc.user == "" {
+		return nil
+	}
+	return uc.cache.PeekRepoList(uc.cacheScope(uc.user), buildDateRange(startDate, endDate))
+}
+
+// SeenSHAs returns the SHAs already viewed for a range. It returns nil when
+// tracking is off or the range was never viewed, so nothing counts as new.
+func (uc *CommitUseCase) SeenSHAs(startDate, endDate string) map[string]bool {
+	if uc.seen == nil {
+		return nil
+	}
+	shas, found, err := uc.seen.Seen(buildDateRange(startDate, endDate))
+	if err != nil {
+		logger.Warn("Failed to read seen commits", "error", err)
+		return nil
+	}
+	if !found {
+		return nil
+	}
+	return shas
+}
+
+// MarkSeen records the commits of the selected repositories as viewed for a
+// range.
+func (uc *CommitUseCase) MarkSeen(startDate, endDate string, commits map[string][]entity.Commit, selected map[string]bool) error {
+	if uc.seen == nil {
+		return nil
+	}
+	var shas []string
+	for repo, repoCommits := range commits {
+		if !selected[repo] {
+			continue
+		}
+		for _, commit := range repoCommits {
+			shas = append(shas, commit.SHA)
+		}
+	}
+	return uc.seen.MarkSeen(buildDateRange(startDate, endDate), shas)
+}
+
+// currentUser returns the authenticated GitHub username, fetching it once.
+func (uc *CommitUseCase) currentUser() (string, error) {
+	if uc.user != "" {
+		return uc.user, nil
+	}
+	user, err := uc.github.GetUser()
+	if err != nil {
+		return "", err
+	}
+	uc.user = user
+	return user, nil
+}
+
+// GetCommitsForAuthor fetches commits for an arbitrary author and date range.
+func (uc *CommitUseCase) GetCommitsForAuthor(author, startDate, endDate string) (*entity.CommitData, error) {
+	if err := uc.validateDateRange(startDate, endDate); err != nil {
+		return nil, err
+	}
+	return uc.fetchForAuthor(author, startDate, endDate)
+}
+
+// CompareWithAuthor compares the authenticated user's commits with another
+// author's commits over the same date range.
+func (uc *CommitUseCase) CompareWithAuthor(other, startDate, endDate string) (*entity.Comparison, error) {
+	if err := uc.validateDateRange(startDate, endDate); err != nil {
+		return nil, err
+	}
+
+	ghUser, err := uc.currentUser()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GitHub user: %w", err)
+	}
+
+	mine, err := uc.fetchForAuthor(ghUser, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	theirs, err := uc.fetchForAuthor(other, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch commits for %s: %w", other, err)
+	}
+
+	comparison := compareCommitData(ghUser, mine, other, theirs)
+	comparison.DateRange = entity.FormatDateDisplay(startDate, endDate)
+	return comparison, nil
+}
+
+// CompareRanges compares the authenticated user's commits over two date
+// ranges, reporting which repositories were active in only one of them and
+// the per-repository count deltas (b minus a).
+func (uc *CommitUseCase) CompareRanges(a, b entity.DateRange) (*entity.Comparison, error) {
+	for _, dr := range []entity.DateRange{a, b} {
+		if err := uc.validateDateRange(dr.StartDate, dr.EndDate); err != nil {
+			return nil, err
+		}
+	}
+
+	ghUser, err := uc.currentUser()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GitHub user: %w", err)
+	}
+
+	left, err := uc.fetchForAuthor(ghUser, a.StartDate, a.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := uc.fetchForAuthor(ghUser, b.StartDate, b.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return compareCommitData(
+		entity.FormatDateDisplay(a.StartDate, a.EndDate), left,
+		entity.FormatDateDisplay(b.StartDate, b.EndDate), right,
+	), nil
+}
+
+// fetchForAuthor fetches an author's commits, consulting the cache first.
+func (uc *CommitUseCase) fetchForAuthor(author, startDate, endDate string) (*entity.CommitData, error) {
+	dateRange := buildDateRange(startDate, endDate)
+
 	// Try cache first.
+	cacheScope := uc.cacheScope(author)
 	if uc.cache != nil {
-		if data, found, err := uc.cache.GetCommits(ghUser, dateRange); err == nil && found {
-			return data, nil
+		if data, found, err := uc.cache.GetCommits(cacheScope, dateRange); err == nil && found {
+			if uc.cachedEntryValid(author, endDate, dateRange, data) {
+				uc.orderCommits(data)
+				return data, nil
+			}
 		}
 	}
 
-	// Fetch from GitHub.
-	data, err := uc.github.FetchCommitsByAuthorAndDate(ghUser, dateRange)
+	return uc.refetchForAuthor(author, dateRange)
+}
+
+// refetchForAuthor fetches an author's commits from GitHub, bypassing the
+// cache, and stores the result in it.
+func (uc *CommitUseCase) refetchForAuthor(author, dateRange string) (*entity.CommitData, error) {
+	data, err := uc.github.FetchCommitsByAuthorAndDate(author, dateRange, uc.dateField)
 	if err != nil {
 		return nil, err
 	}
+	data.FetchedAt = time.Now()
+
+	if uc.includeCoauthored {
+		coauthored, err := uc.github.FetchCoauthoredCommits(author, dateRange, uc.dateField)
+		if err != nil {
+			data.Warning = joinWarnings(data.Warning, "Co-authored commits unavailable: "+err.Error())
+		} else {
+			mergeCommitData(data, coauthored)
+		}
+	}
+
+	if uc.branch != "" {
+		uc.filterByBranch(data)
+	}
+	if uc.pullRequests {
+		uc.attachPullRequests(data)
+	}
 
 	// Store in cache.
 	if uc.cache != nil {
-		_ = uc.cache.SetCommits(ghUser, dateRange, data)
+		_ = uc.cache.SetCommits(uc.cacheScope(author), dateRange, data, uc.cacheTTL)
 	}
 
+	uc.orderCommits(data)
 	return data, nil
 }
 
+// cachedEntryValid reports whether a cached entry can be served. When
+// validation is enabled, entries covering today are checked against the
+// current commit count; a mismatch means commits changed since caching.
+// Capped entries are served as is: they only hold the first results, so
+// their count never matches.
+func (uc *CommitUseCase) cachedEntryValid(author, endDate, dateRange string, data *entity.CommitData) bool {
+	if !uc.validateCache || (endDate != entity.Today() && endDate != time.Now().Format(entity.DateLayout)) {
+		return true
+	}
+	if data.Capped {
+		return true
+	}
+
+	count, err := uc.github.CountCommits(author, dateRange, uc.dateField)
+	if err != nil {
+		// Validation is best-effort; keep serving the cache.
+		logger.Debug("Cache validation failed", "error", err.Error())
+		return true
+	}
+	if count != data.SearchTotal {
+		logger.Info("Cached commits out of date, refetching", "author", author, "date_range", dateRange,
+			"cached", data.SearchTotal, "current", count)
+		return false
+	}
+	return true
+}
+
+// filterByBranch drops commits not reachable from the configured branch and
+// records how many were excluded. Commits whose check fails are kept.
+func (uc *CommitUseCase) filterByBranch(data *entity.CommitData) {
+	var toCheck []entity.Commit
+	for repo, commits := range data.Commits {
+		for _, commit := range commits {
+			if commit.SHA != "" {
+				commit.Repository = repo
+				toCheck = append(toCheck, commit)
+			}
+		}
+	}
+	// Commits whose check failed are kept.
+	onBranch, err := uc.github.CommitsOnBranch(toCheck, uc.branch)
+	if err != nil {
+		logger.Warn("Some branch checks failed", "branch", uc.branch, "error", err)
+	}
+
+	excluded := 0
+	for repo, commits := range data.Commits {
+		var kept []entity.Commit
+		for _, commit := range commits {
+			if on, checked := onBranch[commit.SHA]; checked && !on {
+				excluded++
+				continue
+			}
+			kept = append(kept, commit)
+		}
+
+		if len(kept) == 0 {
+			delete(data.Commits, repo)
+		} else {
+			data.Commits[repo] = kept
+		}
+	}
+
+	var repoList []string
+	for _, repo := range data.RepoList {
+		if _, ok := data.Commits[repo]; ok {
+			repoList = append(repoList, repo)
+		}
+	}
+	data.RepoList = repoList
+
+	data.ExcludedByBranch = excluded
+	if excluded > 0 {
+		data.Warning = joinWarnings(data.Warning, fmt.Sprintf("%d commits not on %s were excluded.", excluded, uc.branch))
+	}
+}
+
+// attachPullRequests sets the PR of each commit that was merged through one.
+// Lookup failures are logged and leave the commit without a PR.
+func (uc *CommitUseCase) attachPullRequests(data *entity.CommitData) {
+	var toCheck []entity.Commit
+	for repo, commits := range data.Commits {
+		for _, commit := range commits {
+			if commit.SHA != "" && !commit.Local {
+				commit.Repository = repo
+				toCheck = append(toCheck, commit)
+			}
+		}
+	}
+	prs, err := uc.github.PullRequestsForCommits(toCheck)
+	if err != nil {
+		logger.Warn("Some pull request lookups failed", "error", err)
+	}
+
+	for _, commits := range data.Commits {
+		for i := range commits {
+			if pr, ok := prs[commits[i].SHA]; ok {
+				commits[i].PR = &pr
+			}
+		}
+	}
+}
+
+// filterByWeekday drops commits not made on one of the configured weekdays
+// and records how many were excluded. Commits without a date are kept.
+func (uc *CommitUseCase) filterByWeekday(data *entity.CommitData) {
+	excluded := 0
+	for repo, commits := range data.Commits {
+		var kept []entity.Commit
+		for _, commit := range commits {
+			if !commit.Date.IsZero() && !slices.Contains(uc.weekdays, commit.Date.Local().Weekday()) {
+				excluded++
+				continue
+			}
+			kept = append(kept, commit)
+		}
+
+		if len(kept) == 0 {
+			delete(data.Commits, repo)
+		} else {
+			data.Commits[repo] = kept
+		}
+	}
+
+	var repoList []string
+	for _, repo := range data.RepoList {
+		if _, ok := data.Commits[repo]; ok {
+			repoList = append(repoList, repo)
+		}
+	}
+	data.RepoList = repoList
+	data.ExcludedByWeekday = excluded
+}
+
+// orderCommits sorts each repository's commits by the configured order.
+// Ties are broken by message and SHA so the result is deterministic.
+func (uc *CommitUseCase) orderCommits(data *entity.CommitData) {
+	if uc.commitOrder == "" || uc.commitOrder == entity.CommitOrderAPI {
+		return
+	}
+
+	for _, commits := range data.Commits {
+		sort.SliceStable(commits, func(i, j int) bool {
+			a, b := commits[i], commits[j]
+			switch uc.commitOrder {
+			case entity.CommitOrderTimeAsc:
+				if !a.Date.Equal(b.Date) {
+					return a.Date.Before(b.Date)
+				}
+			case entity.CommitOrderTimeDesc:
+				if !a.Date.Equal(b.Date) {
+					return a.Date.After(b.Date)
+				}
+			}
+			if a.Message != b.Message {
+				return a.Message < b.Message
+			}
+			return a.SHA < b.SHA
+		})
+	}
+}
+
+// buildDateRange builds the gh search date range query.
+func buildDateRange(startDate, endDate string) string {
+	if startDate == endDate {
+		return startDate
+	}
+	return fmt.Sprintf("%s..%s", startDate, endDate)
+}
+
+// compareCommitData aligns per-repository commit counts of two commit sets.
+func compareCommitData(leftLabel string, left *entity.CommitData, rightLabel string, right *entity.CommitData) *entity.Comparison {
+	comparison := &entity.Comparison{
+		LeftLabel:  leftLabel,
+		RightLabel: rightLabel,
+	}
+
+	repoSet := make(map[string]bool)
+	for repo := range left.Commits {
+		repoSet[repo] = true
+	}
+	for repo := range right.Commits {
+		repoSet[repo] = true
+	}
+
+	repos := make([]string, 0, len(repoSet))
+	for repo := range repoSet {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	for _, repo := range repos {
+		l := len(left.Commits[repo])
+		r := len(right.Commits[repo])
+		if l == 0 && r == 0 {
+			continue
+		}
+		comparison.LeftTotal += l
+		comparison.RightTotal += r
+		comparison.Repos = append(comparison.Repos, entity.RepoComparison{
+			Repository: repo,
+			Left:       l,
+			Right:      r,
+			Delta:      r - l,
+		})
+		switch {
+		case r == 0:
+			comparison.OnlyLeft = append(comparison.OnlyLeft, repo)
+		case l == 0:
+			comparison.OnlyRight = append(comparison.OnlyRight, repo)
+		default:
+			comparison.Both = append(comparison.Both, repo)
+		}
+	}
+
+	return comparison
+}
+
+// cacheScope returns the cache owner key, distinguishing fetch variants so
+// differently-shaped results never share a cache entry.
+func (uc *CommitUseCase) cacheScope(user string) string {
+	scope := user
+	if uc.dateField == entity.DateFieldAuthor {
+		scope += "+" + string(uc.dateField)
+	}
+	if uc.branch != "" {
+		scope += "+branch:" + uc.branch
+	}
+	if uc.includeCoauthored {
+		scope += "+coauthored"
+	}
+	if uc.pullRequests {
+		scope += "+prs"
+	}
+	return scope
+}
+
+// mergeCommitData merges extra commits into data, skipping commits whose SHA
+// is already present.
+func mergeCommitData(data, extra *entity.CommitData) {
+	if data.Commits == nil {
+		data.Commits = make(map[string][]entity.Commit)
+	}
+
+	seen := make(map[string]bool)
+	for _, commits := range data.Commits {
+		for _, commit := range commits {
+			if commit.SHA != "" {
+				seen[commit.SHA] = true
+			}
+		}
+	}
+
+	for repo, commits := range extra.Commits {
+		for _, commit := range commits {
+			if commit.SHA != "" && seen[commit.SHA] {
+				continue
+			}
+			data.Commits[repo] = append(data.Commits[repo], commit)
+		}
+	}
+
+	data.RepoList = data.RepoList[:0]
+	for repo := range data.Commits {
+		data.RepoList = append(data.RepoList, repo)
+	}
+	sort.Strings(data.RepoList)
+
+	data.Warning = joinWarnings(data.Warning, extra.Warning)
+	data.DroppedEmpty += extra.DroppedEmpty
+	data.Capped = data.Capped || extra.Capped
+}
+
+// joinWarnings combines non-empty warnings into a single message.
+func joinWarnings(warnings ...string) string {
+	var parts []string
+	for _, w := range warnings {
+		if w != "" && !slices.Contains(parts, w) {
+			parts = append(parts, w)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
 func (uc *CommitUseCase) validateDateRange(startDate, endDate string) error {
 	startTime, err := time.Parse("2006-01-02", startDate)
 	if err != nil {
@@ -81,9 +771,11 @@ func (uc *CommitUseCase) validateDateRange(startDate, endDate string) error {
 		return fmt.Errorf("start date cannot be after end date")
 	}
 
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
-	if endTime.After(today) {
+	if uc.earliestDate != "" && startDate < uc.earliestDate {
+		return fmt.Errorf("start date cannot be before %s (earliest_date)", uc.earliestDate)
+	}
+
+	if entity.IsFutureDate(endTime) {
 		return fmt.Errorf("end date cannot be in the future")
 	}
 
@@ -96,23 +788,27 @@ func (uc *CommitUseCase) FilterReposByPattern(repos []string, pattern string) []
 		return repos
 	}
 
+	match := compilePattern(pattern)
 	var filtered []string
 	for _, repo := range repos {
-		if matched, _ := matchPattern(pattern, repo); matched {
+		if match(repo) {
 			filtered = append(filtered, repo)
 		}
 	}
 	return filtered
 }
 
-// matchPattern matches a repository name against a pattern.
-func matchPattern(pattern, name string) (bool, error) {
+// compilePattern turns a filter pattern into a case-insensitive matcher for
+// repository names. Plain text matches as a substring; globs are compiled
+// once so a filter over many repos does not recompile per name.
+func compilePattern(pattern string) func(name string) bool {
 	pattern = strings.ToLower(pattern)
-	name = strings.ToLower(name)
 
 	// Simple contains check for non-glob patterns.
 	if !strings.ContainsAny(pattern, "*?[]") {
-		return strings.Contains(name, pattern), nil
+		return func(name string) bool {
+			return strings.Contains(strings.ToLower(name), pattern)
+		}
 	}
 
 	// Convert basic glob to regex; allow * to match across '/'.
@@ -125,9 +821,32 @@ func matchPattern(pattern, name string) (bool, error) {
 	if err != nil {
 		// Fallback to contains for invalid patterns.
 		clean := strings.ReplaceAll(strings.ReplaceAll(pattern, "*", ""), "?", "")
-		return strings.Contains(name, clean), nil
+		return func(name string) bool {
+			return strings.Contains(strings.ToLower(name), clean)
+		}
+	}
+	return func(name string) bool {
+		return re.MatchString(strings.ToLower(name))
 	}
-	return re.MatchString(name), nil
+}
+
+// CountHidden returns how many commits and repositories are hidden from view:
+// repos not matching the visible list, plus commits already dropped while
+// fetching (e.g. by the branch filter).
+func (uc *CommitUseCase) CountHidden(commits map[string][]entity.Commit, repoList, visible []string, droppedAtFetch int) (hiddenCommits, hiddenRepos int) {
+	shown := make(map[string]bool, len(visible))
+	for _, repo := range visible {
+		shown[repo] = true
+	}
+
+	hiddenCommits = droppedAtFetch
+	for _, repo := range repoList {
+		if !shown[repo] {
+			hiddenRepos++
+			hiddenCommits += len(commits[repo])
+		}
+	}
+	return hiddenCommits, hiddenRepos
 }
 
 // CalculateStatistics calculates statistics for selected commits.
@@ -141,6 +860,14 @@ func (uc *CommitUseCase) CalculateStatistics(commits map[string][]entity.Commit,
 			continue
 		}
 		count := len(repoCommits)
+		for _, commit := range repoCommits {
+			if commit.IsRevert() {
+				stats.Reverts++
+				if uc.excludeReverts {
+					count--
+				}
+			}
+		}
 		stats.CommitsPerRepo[repo] = count
 		stats.TotalCommits += count
 		stats.TotalRepositories++
@@ -154,6 +881,138 @@ func (uc *CommitUseCase) CalculateStatistics(commits map[string][]entity.Commit,
 	return stats
 }
 
+// BuildHeatmap counts selected commits by local weekday and hour.
+// Commits without a timestamp are skipped.
+func (uc *CommitUseCase) BuildHeatmap(commits map[string][]entity.Commit, selected map[string]bool) entity.Heatmap {
+	var heatmap entity.Heatmap
+	for repo, repoCommits := range commits {
+		if !selected[repo] {
+			continue
+		}
+		for _, commit := range repoCommits {
+			if commit.Date.IsZero() {
+				continue
+			}
+			local := commit.Date.Local()
+			heatmap[local.Weekday()][local.Hour()]++
+		}
+	}
+	return heatmap
+}
+
+// GroupByOwnership splits repositories into those owned by the authenticated
+// user or a configured org and those the user contributed to. Empty groups
+// are omitted and each group keeps the input order.
+func (uc *CommitUseCase) GroupByOwnership(repos []string) []entity.RepoGroup {
+	var owned, contributed []string
+	for _, repo := range repos {
+		if uc.isOwned(repo) {
+			owned = append(owned, repo)
+		} else {
+			contributed = append(contributed, repo)
+		}
+	}
+
+	var groups []entity.RepoGroup
+	if len(owned) > 0 {
+		groups = append(groups, entity.RepoGroup{Title: "Owned", Repos: owned})
+	}
+	if len(contributed) > 0 {
+		groups = append(groups, entity.RepoGroup{Title: "Contributed", Repos: contributed})
+	}
+	return groups
+}
+
+// isOwned reports whether a repository belongs to the user or an owned org.
+func (uc *CommitUseCase) isOwned(repo string) bool {
+	owner := repoOwner(repo)
+	if owner == "" {
+		return false
+	}
+	if uc.user != "" && strings.EqualFold(owner, uc.user) {
+		return true
+	}
+	for _, org := range uc.ownedOrgs {
+		if strings.EqualFold(owner, org) {
+			return true
+		}
+	}
+	return false
+}
+
+// repoOwner returns the owner part of an owner/name repository.
+func repoOwner(repo string) string {
+	owner, _ := entity.SplitRepo(repo)
+	return owner
+}
+
+// SortRepos returns a copy of repos ordered by the given sort order. Count
+// order is descending with ties broken by name; owner order compares owner,
+// then repository name, case-insensitively.
+func SortRepos(repos []string, commits map[string][]entity.Commit, order entity.RepoSortOrder) []string {
+	sorted := make([]string, len(repos))
+	copy(sorted, repos)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		switch order {
+		case entity.RepoSortCount:
+			if ca, cb := len(commits[a]), len(commits[b]); ca != cb {
+				return ca > cb
+			}
+		case entity.RepoSortOwner:
+			return compareOwnerName(a, b) < 0
+		}
+		return a < b
+	})
+
+	return sorted
+}
+
+// compareOwnerName compares two repositories by owner, then name, ignoring
+// case and falling back to the exact string for a stable order.
+func compareOwnerName(a, b string) int {
+	ownerA, nameA := entity.SplitRepo(a)
+	ownerB, nameB := entity.SplitRepo(b)
+	if c := strings.Compare(strings.ToLower(ownerA), strings.ToLower(ownerB)); c != 0 {
+		return c
+	}
+	if c := strings.Compare(strings.ToLower(nameA), strings.ToLower(nameB)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// InactiveRepos returns the watchlist repositories without any commits, in
+// watchlist order.
+func InactiveRepos(watchlist []string, commits map[string][]entity.Commit) []string {
+	var inactive []string
+	for _, repo := range watchlist {
+		if len(commits[repo]) == 0 {
+			inactive = append(inactive, repo)
+		}
+	}
+	return inactive
+}
+
+// WithoutMerges returns commits with merge commits removed. Repositories
+// left without commits are dropped. The input is not modified.
+func WithoutMerges(commits map[string][]entity.Commit) map[string][]entity.Commit {
+	filtered := make(map[string][]entity.Commit, len(commits))
+	for repo, repoCommits := range commits {
+		var kept []entity.Commit
+		for _, commit := range repoCommits {
+			if !commit.IsMerge() {
+				kept = append(kept, commit)
+			}
+		}
+		if len(kept) > 0 {
+			filtered[repo] = kept
+		}
+	}
+	return filtered
+}
+
 // GetSelectedReposSorted returns a sorted slice of selected repository names.
 func (uc *CommitUseCase) GetSelectedReposSorted(commits map[string][]entity.Commit, selected map[string]bool) []string {
 	var repos []string
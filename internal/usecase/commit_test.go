@@ -0,0 +1,486 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DementevVV/commitsum/internal/domain/entity"
+	"github.com/DementevVV/commitsum/internal/infrastructure/logger"
+	"github.com/DementevVV/commitsum/internal/testutil"
+)
+
+// newTestCommitUseCase returns a use case on fakes for user "octocat".
+func newTestCommitUseCase(t *testing.T, commits map[string][]entity.Commit) (*CommitUseCase, *testutil.FakeGitHub, *testutil.FakeCache) {
+	t.Helper()
+	gh := testutil.NewFakeGitHub(commits)
+	cache := testutil.NewFakeCache()
+	return NewCommitUseCase(gh, cache), gh, cache
+}
+
+func TestGroupByOwnership(t *testing.T) {
+	repos := []string{"octocat/dotfiles", "acme/api", "OctoCat/blog", "other/lib", "acme-labs/tool", "noowner"}
+
+	tests := []struct {
+		name      string
+		user      bool
+		ownedOrgs []string
+		want      []entity.RepoGroup
+	}{
+		{
+			name: "user repos, case-insensitive",
+			user: true,
+			want: []entity.RepoGroup{
+				{Title: "Owned", Repos: []string{"octocat/dotfiles", "OctoCat/blog"}},
+				{Title: "Contributed", Repos: []string{"acme/api", "other/lib", "acme-labs/tool", "noowner"}},
+			},
+		},
+		{
+			name:      "owned orgs",
+			user:      true,
+			ownedOrgs: []string{"ACME"},
+			want: []entity.RepoGroup{
+				{Title: "Owned", Repos: []string{"octocat/dotfiles", "acme/api", "OctoCat/blog"}},
+				{Title: "Contributed", Repos: []string{"other/lib", "acme-labs/tool", "noowner"}},
+			},
+		},
+		{
+			name: "unknown user owns nothing",
+			want: []entity.RepoGroup{
+				{Title: "Contributed", Repos: repos},
+			},
+		},
+		{
+			name:      "everything owned",
+			user:      true,
+			ownedOrgs: []string{"acme", "other", "acme-labs"},
+			want: []entity.RepoGroup{
+				{Title: "Owned", Repos: repos[:5]},
+				{Title: "Contributed", Repos: []string{"noowner"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, _, _ := newTestCommitUseCase(t, nil)
+			uc.SetOwnedOrgs(tt.ownedOrgs)
+			if tt.user {
+				if _, err := uc.currentUser(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := uc.GroupByOwnership(repos); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GroupByOwnership() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheTTLOverride(t *testing.T) {
+	tests := []struct {
+		name string
+		ttl  time.Duration
+	}{
+		{name: "default", ttl: 0},
+		{name: "override", ttl: 90 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, _, cache := newTestCommitUseCase(t, nil)
+			uc.SetCacheTTL(tt.ttl)
+
+			if _, err := uc.GetCommitsForRange("2024-03-01", "2024-03-07"); err != nil {
+				t.Fatal(err)
+			}
+			got, ok := cache.TTL("octocat", buildDateRange("2024-03-01", "2024-03-07"))
+			if !ok {
+				t.Fatal("nothing cached")
+			}
+			if got != tt.ttl {
+				t.Errorf("ttl = %v, want %v", got, tt.ttl)
+			}
+		})
+	}
+}
+
+func TestSortRepos(t *testing.T) {
+	repos := []string{"zeta/api", "Acme/web", "acme/API", "beta/tool", "acme/cli", "Zeta/app"}
+	commits := map[string][]entity.Commit{
+		"zeta/api":  make([]entity.Commit, 3),
+		"acme/cli":  make([]entity.Commit, 3),
+		"beta/tool": make([]entity.Commit, 5),
+		"Acme/web":  make([]entity.Commit, 1),
+	}
+
+	tests := []struct {
+		order entity.RepoSortOrder
+		want  []string
+	}{
+		{
+			order: entity.RepoSortName,
+			want:  []string{"Acme/web", "Zeta/app", "acme/API", "acme/cli", "beta/tool", "zeta/api"},
+		},
+		{
+			order: entity.RepoSortCount,
+			want:  []string{"beta/tool", "acme/cli", "zeta/api", "Acme/web", "Zeta/app", "acme/API"},
+		},
+		{
+			order: entity.RepoSortOwner,
+			want:  []string{"acme/API", "acme/cli", "Acme/web", "beta/tool", "zeta/api", "Zeta/app"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.order), func(t *testing.T) {
+			input := slices.Clone(repos)
+			if got := SortRepos(input, commits, tt.order); !slices.Equal(got, tt.want) {
+				t.Errorf("SortRepos() = %v, want %v", got, tt.want)
+			}
+			if !slices.Equal(input, repos) {
+				t.Errorf("SortRepos modified its input: %v", input)
+			}
+		})
+	}
+}
+
+func TestCompareOwnerName(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "acme/api", b: "beta/api", want: -1},
+		{a: "ACME/zeta", b: "acme/alpha", want: 1},
+		{a: "acme/Api", b: "acme/api", want: -1},
+		{a: "acme/api", b: "acme/api", want: 0},
+		{a: "acme-labs/a", b: "acme/z", want: 1},
+		{a: "acme/platform/api", b: "acme/web", want: 1},
+		{a: "acme/platform/api", b: "acme/platform/web", want: -1},
+		{a: "noowner", b: "acme/api", want: -1},
+	}
+
+	for _, tt := range tests {
+		if got := compareOwnerName(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareOwnerName(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFilterReposByPattern(t *testing.T) {
+	repos := []string{"acme/api", "acme/web", "octocat/dotfiles", "Acme/API-docs", "odd/[lib]"}
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{pattern: "", want: repos},
+		{pattern: "api", want: []string{"acme/api", "Acme/API-docs"}},
+		{pattern: "acme/*", want: []string{"acme/api", "acme/web", "Acme/API-docs"}},
+		{pattern: "*/a?i", want: []string{"acme/api"}},
+		{pattern: "*dot*", want: []string{"octocat/dotfiles"}},
+		// Brackets match literally rather than failing as a character class.
+		{pattern: "odd/[lib]", want: []string{"odd/[lib]"}},
+		{pattern: "*[lib*", want: []string{"odd/[lib]"}},
+		{pattern: "*[*", want: []string{"odd/[lib]"}},
+		{pattern: "missing", want: nil},
+	}
+
+	uc := NewCommitUseCase(nil, nil)
+	for _, tt := range tests {
+		if got := uc.FilterReposByPattern(repos, tt.pattern); !slices.Equal(got, tt.want) {
+			t.Errorf("FilterReposByPattern(%q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func BenchmarkFilterReposByPattern(b *testing.B) {
+	repos := make([]string, 1000)
+	for i := range repos {
+		repos[i] = fmt.Sprintf("org-%d/repo-%03d", i%10, i)
+	}
+	const pattern = "org-3/*-?5*"
+	uc := NewCommitUseCase(nil, nil)
+
+	b.Run("compiled once", func(b *testing.B) {
+		for b.Loop() {
+			uc.FilterReposByPattern(repos, pattern)
+		}
+	})
+	// The previous behavior, compiling the pattern for every repository.
+	b.Run("compiled per repo", func(b *testing.B) {
+		for b.Loop() {
+			var filtered []string
+			for _, repo := range repos {
+				if compilePattern(pattern)(repo) {
+					filtered = append(filtered, repo)
+				}
+			}
+		}
+	})
+}
+
+func TestPullRequestGrouping(t *testing.T) {
+	commits := map[string][]entity.Commit{
+		"acme/api": {
+			{Repository: "acme/api", Message: "add login form", SHA: "a1"},
+			{Repository: "acme/api", Message: "add login api", SHA: "a2"},
+			{Repository: "acme/api", Message: "fix typo", SHA: "a3"},
+		},
+	}
+	prs := map[string]entity.PullRequest{
+		"a1": {Number: 42, Title: "Login"},
+		"a2": {Number: 42, Title: "Login"},
+	}
+
+	tests := []struct {
+		name string
+		prs  map[string]entity.PullRequest
+		want string
+	}{
+		{name: "PR data", prs: prs, want: "  #42 Login\n    - add login form\n    - add login api\n  - fix typo\n"},
+		{name: "no PR data", want: "  - add login form\n  - add login api\n  - fix typo\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, gh, _ := newTestCommitUseCase(t, commits)
+			gh.PRs = tt.prs
+			uc.SetPullRequests(true)
+
+			data, err := uc.GetCommitsForRange("2024-03-01", "2024-03-01")
+			if err != nil {
+				t.Fatal(err)
+			}
+			selected := map[string]bool{"acme/api": true}
+			stats := uc.CalculateStatistics(data.Commits, selected)
+			got, err := newTestExportUseCase().Render(entity.FormatText, data.Commits, selected, "2024-03-01", stats, nil, entity.ExportOptions{GroupByPR: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("summary lacks\n%s\ngot:\n%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCachedEntryValidation(t *testing.T) {
+	today := entity.Today()
+	commits := map[string][]entity.Commit{
+		"acme/api": {{Repository: "acme/api", Message: "Add login", SHA: "a1"}},
+	}
+
+	tests := []struct {
+		name        string
+		validate    bool
+		start, end  string
+		capped      bool
+		count       int
+		wantFetches int
+		wantCounts  int
+	}{
+		{name: "count unchanged", validate: true, start: today, end: today, count: -1, wantFetches: 1, wantCounts: 1},
+		{name: "count changed", validate: true, start: today, end: today, count: 2, wantFetches: 2, wantCounts: 1},
+		{name: "capped entry", validate: true, start: today, end: today, capped: true, count: 5000, wantFetches: 1, wantCounts: 0},
+		{name: "past range", validate: true, start: "2024-03-01", end: "2024-03-07", count: 2, wantFetches: 1, wantCounts: 0},
+		{name: "validation off", validate: false, start: today, end: today, count: 2, wantFetches: 1, wantCounts: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, gh, _ := newTestCommitUseCase(t, commits)
+			uc.SetValidateCache(tt.validate)
+			gh.Capped = tt.capped
+
+			if _, err := uc.GetCommitsForRange(tt.start, tt.end); err != nil {
+				t.Fatal(err)
+			}
+			gh.Count = tt.count
+			if _, err := uc.GetCommitsForRange(tt.start, tt.end); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := gh.FetchCount(); got != tt.wantFetches {
+				t.Errorf("%d fetches, want %d", got, tt.wantFetches)
+			}
+			if gh.Counts != tt.wantCounts {
+				t.Errorf("%d count queries, want %d", gh.Counts, tt.wantCounts)
+			}
+		})
+	}
+}
+
+// captureLog collects log output at info level and above for the test.
+func captureLog(t *testing.T) *strings.Builder {
+	t.Helper()
+	var buf strings.Builder
+	t.Cleanup(logger.SetOutput(&buf, logger.LevelInfo))
+	return &buf
+}
+
+func TestGetCommitsForRangeLogsPerformance(t *testing.T) {
+	today := entity.Today()
+	uc, gh, _ := newTestCommitUseCase(t, map[string][]entity.Commit{
+		"acme/api": {
+			{Repository: "acme/api", Message: "Add login", SHA: "a1"},
+			{Repository: "acme/api", Message: "Fix logout", SHA: "a2"},
+		},
+		"acme/web": {{Repository: "acme/web", Message: "Style header", SHA: "w1"}},
+	})
+
+	steps := []struct {
+		name    string
+		fetch   error
+		wantLog string
+	}{
+		{name: "cold", wantLog: "| duration_ms=%d cache=miss repos=2 commits=3"},
+		{name: "warm", wantLog: "| duration_ms=%d cache=hit repos=2 commits=3"},
+		{name: "failed", fetch: errors.New("network down"), wantLog: "| duration_ms=%d cache=error"},
+	}
+
+	for _, step := range steps {
+		log := captureLog(t)
+		start, end := today, today
+		if step.fetch != nil {
+			// A range not cached yet, so the fetch error surfaces.
+			gh.Err = step.fetch
+			start = time.Now().AddDate(0, 0, -1).Format(entity.DateLayout)
+		}
+		_, _ = uc.GetCommitsForRange(start, end)
+
+		lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+		var perf []string
+		for _, line := range lines {
+			if strings.HasPrefix(line, "[INFO] Performance: get_commits ") {
+				perf = append(perf, line)
+			}
+		}
+		if len(perf) != 1 {
+			t.Fatalf("%s: got %d get_commits performance entries, want 1:\n%s", step.name, len(perf), log)
+		}
+		var ms int64
+		if _, err := fmt.Sscanf(perf[0], "[INFO] Performance: get_commits "+step.wantLog, &ms); err != nil {
+			t.Errorf("%s: entry %q does not match %q: %v", step.name, perf[0], step.wantLog, err)
+		}
+	}
+}
+
+func TestCompareCommitData(t *testing.T) {
+	n := func(repo string, count int) []entity.Commit {
+		commits := make([]entity.Commit, count)
+		for i := range commits {
+			commits[i] = entity.Commit{Repository: repo, Message: fmt.Sprintf("Change %d", i), SHA: fmt.Sprintf("%s-%d", repo, i)}
+		}
+		return commits
+	}
+
+	tests := []struct {
+		name  string
+		left  map[string][]entity.Commit
+		right map[string][]entity.Commit
+		want  entity.Comparison
+	}{
+		{
+			name:  "mixed",
+			left:  map[string][]entity.Commit{"acme/api": n("acme/api", 3), "acme/web": n("acme/web", 1)},
+			right: map[string][]entity.Commit{"acme/api": n("acme/api", 1), "octocat/dotfiles": n("octocat/dotfiles", 2)},
+			want: entity.Comparison{
+				LeftTotal:  4,
+				RightTotal: 3,
+				Repos: []entity.RepoComparison{
+					{Repository: "acme/api", Left: 3, Right: 1, Delta: -2},
+					{Repository: "acme/web", Left: 1, Right: 0, Delta: -1},
+					{Repository: "octocat/dotfiles", Left: 0, Right: 2, Delta: 2},
+				},
+				OnlyLeft:  []string{"acme/web"},
+				OnlyRight: []string{"octocat/dotfiles"},
+				Both:      []string{"acme/api"},
+			},
+		},
+		{
+			name:  "identical",
+			left:  map[string][]entity.Commit{"acme/api": n("acme/api", 2)},
+			right: map[string][]entity.Commit{"acme/api": n("acme/api", 2)},
+			want: entity.Comparison{
+				LeftTotal:  2,
+				RightTotal: 2,
+				Repos:      []entity.RepoComparison{{Repository: "acme/api", Left: 2, Right: 2, Delta: 0}},
+				Both:       []string{"acme/api"},
+			},
+		},
+		{
+			name:  "empty left",
+			right: map[string][]entity.Commit{"acme/api": n("acme/api", 2)},
+			want: entity.Comparison{
+				RightTotal: 2,
+				Repos:      []entity.RepoComparison{{Repository: "acme/api", Left: 0, Right: 2, Delta: 2}},
+				OnlyRight:  []string{"acme/api"},
+			},
+		},
+		{
+			name:  "repo without commits on either side",
+			left:  map[string][]entity.Commit{"acme/api": {}},
+			right: map[string][]entity.Commit{"acme/api": nil},
+			want:  entity.Comparison{},
+		},
+		{
+			name: "both empty",
+			want: entity.Comparison{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want.LeftLabel, tt.want.RightLabel = "A", "B"
+			got := compareCommitData("A", testutil.NewCommitData(tt.left), "B", testutil.NewCommitData(tt.right))
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("compareCommitData() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareRanges(t *testing.T) {
+	uc, gh, _ := newTestCommitUseCase(t, map[string][]entity.Commit{
+		"acme/api": {{Repository: "acme/api", Message: "Add login", SHA: "a1"}},
+	})
+	a := entity.DateRange{StartDate: "2024-03-01", EndDate: "2024-03-07"}
+	b := entity.DateRange{StartDate: "2024-03-08", EndDate: "2024-03-14"}
+
+	got, err := uc.CompareRanges(a, b)
+	if err != nil {
+		t.Fatalf("CompareRanges: %v", err)
+	}
+	if got.LeftLabel != entity.FormatDateDisplay(a.StartDate, a.EndDate) || got.RightLabel != entity.FormatDateDisplay(b.StartDate, b.EndDate) {
+		t.Errorf("labels = %q, %q, want the two ranges", got.LeftLabel, got.RightLabel)
+	}
+	if !slices.Equal(got.Both, []string{"acme/api"}) || got.LeftTotal != 1 || got.RightTotal != 1 {
+		t.Errorf("comparison = %+v, want acme/api on both sides", got)
+	}
+	if n := len(gh.Fetches); n != 2 {
+		t.Fatalf("fetched %d times, want once per range", n)
+	}
+
+	// A repeat is served from the cache.
+	if _, err := uc.CompareRanges(a, b); err != nil {
+		t.Fatalf("CompareRanges: %v", err)
+	}
+	if n := len(gh.Fetches); n != 2 {
+		t.Errorf("fetched %d times after a repeat, want the cache to serve it", n)
+	}
+
+	// An invalid range fails before fetching anything.
+	future := time.Now().AddDate(0, 0, 2).Format(entity.DateLayout)
+	if _, err := uc.CompareRanges(a, entity.DateRange{StartDate: future, EndDate: future}); err == nil {
+		t.Error("CompareRanges with a future range succeeded, want an error")
+	}
+	if n := len(gh.Fetches); n != 2 {
+		t.Errorf("fetched %d times for an invalid range, want none", n)
+	}
+}
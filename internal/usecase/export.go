@@ -2,54 +2,228 @@ package usecase
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"sort"
 	"strings"
 	"text/template"
 	"time"
+	"unicode"
 
 	"github.com/DementevVV/commitsum/internal/domain/entity"
 )
 
 // ExportUseCase handles export-related business logic.
-type ExportUseCase struct{}
+type ExportUseCase struct {
+	filenameDateFormat string
+	groupRepos         func(repos []string) []entity.RepoGroup
+	prBodyTemplate     string
+	prBodyFile         string
+	postProcessCommand string
+	version            string
+	templates          map[string]string
+	repoOrder          entity.RepoSortOrder
+	crlf               bool
+	bom                bool
+	// now returns the time stamped into export footers.
+	now func() time.Time
+}
 
 // NewExportUseCase creates a new ExportUseCase.
 func NewExportUseCase() *ExportUseCase {
-	return &ExportUseCase{}
+	return &ExportUseCase{
+		filenameDateFormat: entity.DateLayout,
+		now:                time.Now,
+	}
+}
+
+// SetFilenameDateFormat sets the Go time layout used in generated filenames.
+func (uc *ExportUseCase) SetFilenameDateFormat(layout string) {
+	uc.filenameDateFormat = layout
+}
+
+// SetRepoGrouper sets a function splitting repositories into titled sections.
+// A nil grouper renders a single untitled section.
+func (uc *ExportUseCase) SetRepoGrouper(grouper func(repos []string) []entity.RepoGroup) {
+	uc.groupRepos = grouper
+}
+
+// SetPRBodyTemplate sets the template for PR body exports. An empty template
+// uses DefaultPRBodyTemplate.
+func (uc *ExportUseCase) SetPRBodyTemplate(tmpl string) {
+	uc.prBodyTemplate = tmpl
+}
+
+// SetPRBodyFile sets the file PR body exports are written to. When empty,
+// .git/PULLREQUEST_MSG is used inside a repository and PULLREQUEST_MSG.md
+// elsewhere.
+func (uc *ExportUseCase) SetPRBodyFile(path string) {
+	uc.prBodyFile = path
+}
+
+// SetPostProcessCommand sets a shell command that export content is piped
+// through (stdin to stdout). An empty command disables post-processing.
+func (uc *ExportUseCase) SetPostProcessCommand(command string) {
+	uc.postProcessCommand = command
+}
+
+// SetVersion sets the commitsum version shown in export footers.
+func (uc *ExportUseCase) SetVersion(version string) {
+	uc.version = version
+}
+
+// footerLine returns the provenance line for exports, e.g.
+// "Generated by commitsum v1.2.0 at 2026-02-02 18:04:05". Deterministic
+// exports leave out the time.
+func (uc *ExportUseCase) footerLine(deterministic bool) string {
+	tool := "commitsum"
+	switch {
+	case uc.version == "":
+	case unicode.IsDigit(rune(uc.version[0])):
+		tool += " v" + uc.version
+	default:
+		tool += " " + uc.version
+	}
+	if deterministic {
+		return "Generated by " + tool
+	}
+	return fmt.Sprintf("Generated by %s at %s", tool, uc.now().Format("2006-01-02 15:04:05"))
+}
+
+// SetTemplates sets custom templates keyed by export format name. Formats
+// without a template use the built-in renderer.
+func (uc *ExportUseCase) SetTemplates(templates map[string]string) {
+	uc.templates = templates
+}
+
+// SetRepoOrder sets how repository sections are ordered in text, markdown
+// and task list exports, using the same orders as the repository list. The
+// default is alphabetical.
+func (uc *ExportUseCase) SetRepoOrder(order entity.RepoSortOrder) {
+	uc.repoOrder = order
+}
+
+// SetFileEncoding sets how SaveToFile writes exports: lineEnding "crlf" uses
+// Windows line endings (anything else keeps LF) and bom prepends a UTF-8 byte
+// order mark. Clipboard content is unaffected.
+func (uc *ExportUseCase) SetFileEncoding(lineEnding string, bom bool) {
+	uc.crlf = lineEnding == "crlf"
+	uc.bom = bom
+}
+
+// TemplateFor returns the custom template configured for a format, if any.
+func (uc *ExportUseCase) TemplateFor(format entity.ExportFormat) (string, bool) {
+	tmpl, ok := uc.templates[string(format)]
+	return tmpl, ok && tmpl != ""
+}
+
+// repoHeading returns "alias (owner/name)" for aliased repos, else the name.
+func repoHeading(repo string, opts entity.ExportOptions) string {
+	if alias := opts.RepoAliases[repo]; alias != "" {
+		return fmt.Sprintf("%s (%s)", alias, repo)
+	}
+	return repo
+}
+
+// commitLine returns the cased message, prefixed with the short SHA when
+// ShowSHA is set. code wraps the SHA in backticks for markdown.
+func commitLine(commit entity.Commit, opts entity.ExportOptions, code bool) string {
+	message := opts.MessageCase.Apply(commit.Message)
+	sha := commit.ShortSHA()
+	if !opts.ShowSHA || sha == "" {
+		return message
+	}
+	if code {
+		sha = "`" + sha + "`"
+	}
+	return sha + " " + message
+}
+
+// prGroups groups a repository's commits by pull request when enabled and
+// PR data is present, otherwise returns them as a single ungrouped run.
+func prGroups(commits []entity.Commit, opts entity.ExportOptions) []entity.PRGroup {
+	if opts.GroupByPR {
+		if groups := entity.GroupByPR(commits); groups != nil {
+			return groups
+		}
+	}
+	return []entity.PRGroup{{Commits: commits}}
+}
+
+// repoGroups splits repos into sections using the configured grouper.
+func (uc *ExportUseCase) repoGroups(repos []string) []entity.RepoGroup {
+	if uc.groupRepos == nil {
+		return []entity.RepoGroup{{Repos: repos}}
+	}
+	return uc.groupRepos(repos)
 }
 
 // ExportToText generates plain text output.
-func (uc *ExportUseCase) ExportToText(commits map[string][]entity.Commit, selected map[string]bool, dateStr string, stats *entity.Statistics) string {
+func (uc *ExportUseCase) ExportToText(commits map[string][]entity.Commit, selected map[string]bool, dateStr string, stats *entity.Statistics, opts entity.ExportOptions) string {
 	var output strings.Builder
-	output.WriteString("Commit Summary - " + dateStr + "\n\n")
-
-	repos := getSelectedReposSorted(commits, selected)
+	repos := uc.orderedRepos(commits, selected)
+	totalCommits := 0
 	for _, repo := range repos {
-		repoCommits := commits[repo]
-		output.WriteString(fmt.Sprintf("[%s]\n", repo))
-		for _, commit := range repoCommits {
-			output.WriteString(fmt.Sprintf("  - %s\n", commit.Message))
+		totalCommits += len(commits[repo])
+	}
+	output.WriteString(entity.SubjectLine(totalCommits, len(repos), dateStr) + "\n\n")
+
+	for _, group := range uc.repoGroups(repos) {
+		if group.Title != "" {
+			output.WriteString(fmt.Sprintf("== %s ==\n\n", group.Title))
+		}
+		for _, repo := range group.Repos {
+			repoCommits := commits[repo]
+			output.WriteString(fmt.Sprintf("[%s]\n", repoHeading(repo, opts)))
+			for _, group := range prGroups(repoCommits, opts) {
+				indent := "  "
+				if group.PR != nil {
+					output.WriteString(fmt.Sprintf("  %s\n", group.PR.Heading()))
+					indent = "    "
+				}
+				for _, commit := range group.Commits {
+					output.WriteString(fmt.Sprintf("%s%s %s\n", indent, opts.BulletMarker(), commitLine(commit, opts, false)))
+				}
+			}
+			output.WriteString("\n")
 		}
-		output.WriteString("\n")
+	}
+
+	if inactive := InactiveRepos(opts.Watchlist, commits); len(inactive) > 0 {
+		output.WriteString(fmt.Sprintf("No activity: %s\n\n", strings.Join(inactive, ", ")))
 	}
 
 	if stats != nil {
 		output.WriteString("---\n")
-		output.WriteString(fmt.Sprintf("Statistics: %d commits across %d repositories\n", stats.TotalCommits, stats.TotalRepositories))
+		output.WriteString(fmt.Sprintf("Statistics: %d commits across %d repositories", stats.TotalCommits, stats.TotalRepositories))
+		if stats.Reverts > 0 {
+			output.WriteString(fmt.Sprintf(", %d reverts", stats.Reverts))
+		}
+		output.WriteString("\n")
 		if stats.MostActiveRepo != "" {
 			output.WriteString(fmt.Sprintf("Most active: %s (%d commits)\n", stats.MostActiveRepo, stats.MaxCommits))
 		}
 	}
 
+	if opts.Footer {
+		if stats == nil {
+			output.WriteString("---\n")
+		}
+		output.WriteString(uc.footerLine(opts.Deterministic) + "\n")
+	}
+
 	return output.String()
 }
 
 // ExportToMarkdown generates markdown output.
-func (uc *ExportUseCase) ExportToMarkdown(commits map[string][]entity.Commit, selected map[string]bool, dateStr string, stats *entity.Statistics) string {
+func (uc *ExportUseCase) ExportToMarkdown(commits map[string][]entity.Commit, selected map[string]bool, dateStr string, stats *entity.Statistics, opts entity.ExportOptions) string {
 	var output strings.Builder
 	output.WriteString("# Commit Summary\n\n")
 	output.WriteString(fmt.Sprintf("**Date:** %s\n\n", dateStr))
@@ -57,6 +231,9 @@ func (uc *ExportUseCase) ExportToMarkdown(commits map[string][]entity.Commit, se
 	if stats != nil {
 		output.WriteString("## Statistics\n\n")
 		output.WriteString(fmt.Sprintf("- **Total Commits:** %d\n", stats.TotalCommits))
+		if stats.Reverts > 0 {
+			output.WriteString(fmt.Sprintf("- **Reverts:** %d\n", stats.Reverts))
+		}
 		output.WriteString(fmt.Sprintf("- **Repositories:** %d\n", stats.TotalRepositories))
 		if stats.MostActiveRepo != "" {
 			output.WriteString(fmt.Sprintf("- **Most Active:** %s (%d commits)\n", stats.MostActiveRepo, stats.MaxCommits))
@@ -64,52 +241,286 @@ func (uc *ExportUseCase) ExportToMarkdown(commits map[string][]entity.Commit, se
 		output.WriteString("\n")
 	}
 
-	output.WriteString("## Commits\n\n")
+	repos := uc.orderedRepos(commits, selected)
+	for _, group := range uc.repoGroups(repos) {
+		title := group.Title
+		if title == "" {
+			title = "Commits"
+		}
+		output.WriteString(fmt.Sprintf("## %s\n\n", title))
 
-	repos := getSelectedReposSorted(commits, selected)
-	for _, repo := range repos {
-		repoCommits := commits[repo]
-		output.WriteString(fmt.Sprintf("### %s\n\n", repo))
-		for _, commit := range repoCommits {
-			output.WriteString(fmt.Sprintf("- %s\n", commit.Message))
+		for _, repo := range group.Repos {
+			repoCommits := commits[repo]
+			if opts.CollapsibleMarkdown {
+				output.WriteString("<details>\n")
+				output.WriteString(fmt.Sprintf("<summary>%s (%d)</summary>\n\n", repoHeading(repo, opts), len(repoCommits)))
+			} else {
+				output.WriteString(fmt.Sprintf("### %s\n\n", repoHeading(repo, opts)))
+			}
+			for _, group := range prGroups(repoCommits, opts) {
+				indent := ""
+				if group.PR != nil {
+					output.WriteString(fmt.Sprintf("%s **%s**\n", opts.BulletMarker(), group.PR.Heading()))
+					indent = "  "
+				}
+				for _, commit := range group.Commits {
+					output.WriteString(fmt.Sprintf("%s%s %s\n", indent, opts.BulletMarker(), commitLine(commit, opts, true)))
+				}
+			}
+			if opts.CollapsibleMarkdown {
+				output.WriteString("\n</details>\n")
+			}
+			output.WriteString("\n")
+		}
+	}
+
+	if inactive := InactiveRepos(opts.Watchlist, commits); len(inactive) > 0 {
+		output.WriteString("## No Activity\n\n")
+		for _, repo := range inactive {
+			output.WriteString(fmt.Sprintf("%s %s\n", opts.BulletMarker(), repoHeading(repo, opts)))
 		}
 		output.WriteString("\n")
 	}
 
-	output.WriteString("---\n")
-	output.WriteString(fmt.Sprintf("*Generated by commitsum on %s*\n", time.Now().Format("2006-01-02 15:04:05")))
+	if opts.Footer {
+		output.WriteString("---\n")
+		output.WriteString("*" + uc.footerLine(opts.Deterministic) + "*\n")
+	}
 
 	return output.String()
 }
 
 // ExportToJSON generates JSON output.
-func (uc *ExportUseCase) ExportToJSON(commits map[string][]entity.Commit, selected map[string]bool, dateStr string, stats *entity.Statistics) (string, error) {
-	export := entity.NewSummaryExport(dateStr)
+func (uc *ExportUseCase) ExportToJSON(commits map[string][]entity.Commit, selected map[string]bool, dateStr string, stats *entity.Statistics, opts entity.ExportOptions) (string, error) {
+	export := uc.summaryExport(commits, selected, dateStr, opts)
 	export.Stats = stats
+	if opts.Deterministic {
+		export.GeneratedAt = ""
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// Checksum returns the content checksum of the selected commits, as included
+// in JSON exports.
+func (uc *ExportUseCase) Checksum(commits map[string][]entity.Commit, selected map[string]bool, opts entity.ExportOptions) string {
+	return uc.summaryExport(commits, selected, "", opts).ContentChecksum
+}
+
+// summaryExport builds the JSON export structure for the selected commits,
+// without stats.
+func (uc *ExportUseCase) summaryExport(commits map[string][]entity.Commit, selected map[string]bool, dateStr string, opts entity.ExportOptions) *entity.SummaryExport {
+	export := entity.NewSummaryExport(dateStr)
 
 	repos := getSelectedReposSorted(commits, selected)
+	if uc.groupRepos != nil {
+		export.Groups = make(map[string][]string)
+		for _, group := range uc.groupRepos(repos) {
+			export.Groups[group.Title] = group.Repos
+		}
+	}
 	for _, repo := range repos {
 		repoCommits := commits[repo]
 		export.TotalRepos++
 		for _, commit := range repoCommits {
 			export.Commits[repo] = append(export.Commits[repo], entity.CommitExport{
 				Repository: repo,
-				Message:    commit.Message,
+				Alias:      opts.RepoAliases[repo],
+				Message:    opts.MessageCase.Apply(commit.Message),
+				SHA:        commit.SHA,
+				Coauthored: commit.Coauthored,
+				Local:      commit.Local,
+				Revert:     commit.IsRevert(),
 			})
 			export.TotalCommits++
 		}
 	}
+	export.NoActivity = InactiveRepos(opts.Watchlist, commits)
+	export.Subject = export.SubjectLine()
+	export.ContentChecksum = export.Checksum()
+	return export
+}
+
+// ExportToCompact renders a one-line digest for chat, e.g.
+// "2026-02-02: acme/api: 5, acme/web: 3", busiest repository first.
+// Repository aliases replace the full name.
+func (uc *ExportUseCase) ExportToCompact(commits map[string][]entity.Commit, selected map[string]bool, dateStr string, opts entity.ExportOptions) string {
+	repos := SortRepos(getSelectedReposSorted(commits, selected), commits, entity.RepoSortCount)
+	if len(repos) == 0 {
+		return dateStr + ": no commits\n"
+	}
+
+	parts := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		name := repo
+		if alias := opts.RepoAliases[repo]; alias != "" {
+			name = alias
+		}
+		parts = append(parts, fmt.Sprintf("%s: %d", name, len(commits[repo])))
+	}
+	return dateStr + ": " + strings.Join(parts, ", ") + "\n"
+}
+
+// ExportToLegacyJSON renders the selection in the pre-refactor APIResponse
+// shape for backward compatibility. Presentation options do not apply:
+// messages are emitted as fetched.
+func (uc *ExportUseCase) ExportToLegacyJSON(commits map[string][]entity.Commit, selected map[string]bool) (string, error) {
+	export := entity.LegacyExport{Items: []entity.LegacyItem{}}
+	for _, repo := range getSelectedReposSorted(commits, selected) {
+		for _, commit := range commits[repo] {
+			var item entity.LegacyItem
+			item.Repository.FullName = repo
+			item.Commit.Message = commit.Message
+			export.Items = append(export.Items, item)
+		}
+	}
 
 	data, err := json.MarshalIndent(export, "", "  ")
 	if err != nil {
 		return "", err
 	}
-
 	return string(data), nil
 }
 
+// ExportToGitHubTasks renders a GitHub task list with one unchecked item per
+// commit under a heading per repository, for pasting into an issue body.
+func (uc *ExportUseCase) ExportToGitHubTasks(commits map[string][]entity.Commit, selected map[string]bool, dateStr string, opts entity.ExportOptions) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("## Commits for %s\n\n", dateStr))
+
+	for _, repo := range uc.orderedRepos(commits, selected) {
+		output.WriteString(fmt.Sprintf("### %s\n\n", repoHeading(repo, opts)))
+		for _, commit := range commits[repo] {
+			output.WriteString(fmt.Sprintf("- [ ] %s\n", commitLine(commit, opts, true)))
+		}
+		output.WriteString("\n")
+	}
+
+	return output.String()
+}
+
+// ExportToDaily renders a markdown report with a heading per day, oldest
+// first, and the day's commits grouped by repository. Days come from each
+// commit's local date; commits without one are listed last.
+func (uc *ExportUseCase) ExportToDaily(commits map[string][]entity.Commit, selected map[string]bool, dateStr string, opts entity.ExportOptions) string {
+	const unknownDay = ""
+	days := make(map[string]map[string][]entity.Commit)
+	for _, repo := range getSelectedReposSorted(commits, selected) {
+		for _, commit := range commits[repo] {
+			day := unknownDay
+			if !commit.Date.IsZero() {
+				day = commit.Date.Local().Format(entity.DateLayout)
+			}
+			if days[day] == nil {
+				days[day] = make(map[string][]entity.Commit)
+			}
+			days[day][repo] = append(days[day][repo], commit)
+		}
+	}
+
+	order := make([]string, 0, len(days))
+	for day := range days {
+		if day != unknownDay {
+			order = append(order, day)
+		}
+	}
+	sort.Strings(order)
+	if _, ok := days[unknownDay]; ok {
+		order = append(order, unknownDay)
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("# Commits by Day - %s\n\n", dateStr))
+	for _, day := range order {
+		heading := "Unknown date"
+		if t, err := time.Parse(entity.DateLayout, day); err == nil {
+			heading = t.Weekday().String() + ", " + entity.FormatDate(day, entity.DisplayDateFormat)
+		}
+		output.WriteString(fmt.Sprintf("## %s\n\n", heading))
+
+		repos := make([]string, 0, len(days[day]))
+		for repo := range days[day] {
+			repos = append(repos, repo)
+		}
+		sort.Strings(repos)
+		for _, repo := range repos {
+			output.WriteString(fmt.Sprintf("### %s\n\n", repoHeading(repo, opts)))
+			for _, commit := range days[day][repo] {
+				output.WriteString(fmt.Sprintf("%s %s\n", opts.BulletMarker(), commitLine(commit, opts, true)))
+			}
+			output.WriteString("\n")
+		}
+	}
+
+	if opts.Footer {
+		output.WriteString("---\n")
+		output.WriteString("*" + uc.footerLine(opts.Deterministic) + "*\n")
+	}
+	return output.String()
+}
+
+// ExportToHeatmapCSV renders a weekday × hour heatmap as CSV.
+func (uc *ExportUseCase) ExportToHeatmapCSV(heatmap entity.Heatmap) string {
+	var output strings.Builder
+	output.WriteString("weekday")
+	for hour := 0; hour < 24; hour++ {
+		output.WriteString(fmt.Sprintf(",%02d", hour))
+	}
+	output.WriteString("\n")
+
+	for day, hours := range heatmap {
+		output.WriteString(time.Weekday(day).String())
+		for _, count := range hours {
+			output.WriteString(fmt.Sprintf(",%d", count))
+		}
+		output.WriteString("\n")
+	}
+
+	return output.String()
+}
+
+// Render renders the selection in the given format, preferring a configured
+// custom template. heatmap is only called for the heatmap format.
+func (uc *ExportUseCase) Render(format entity.ExportFormat, commits map[string][]entity.Commit, selected map[string]bool, dateStr string, stats *entity.Statistics, heatmap func() entity.Heatmap, opts entity.ExportOptions) (string, error) {
+	if !opts.IncludeStats {
+		stats = nil
+	}
+	if opts.Deterministic {
+		commits = sortedCommits(commits)
+	}
+	if tmpl, ok := uc.TemplateFor(format); ok {
+		return uc.ExportWithTemplate(commits, selected, dateStr, stats, tmpl, opts)
+	}
+
+	switch format {
+	case entity.FormatMarkdown:
+		return uc.ExportToMarkdown(commits, selected, dateStr, stats, opts), nil
+	case entity.FormatJSON:
+		return uc.ExportToJSON(commits, selected, dateStr, stats, opts)
+	case entity.FormatHeatmap:
+		return uc.ExportToHeatmapCSV(heatmap()), nil
+	case entity.FormatPRBody:
+		return uc.ExportToPRBody(commits, selected, dateStr, stats, opts)
+	case entity.FormatGitHubTasks:
+		return uc.ExportToGitHubTasks(commits, selected, dateStr, opts), nil
+	case entity.FormatDaily:
+		return uc.ExportToDaily(commits, selected, dateStr, opts), nil
+	case entity.FormatLegacyJSON:
+		return uc.ExportToLegacyJSON(commits, selected)
+	case entity.FormatCompact:
+		return uc.ExportToCompact(commits, selected, dateStr, opts), nil
+	default:
+		return uc.ExportToText(commits, selected, dateStr, stats, opts), nil
+	}
+}
+
 // ExportWithTemplate generates output using a custom template.
-func (uc *ExportUseCase) ExportWithTemplate(commits map[string][]entity.Commit, selected map[string]bool, dateStr string, stats *entity.Statistics, tmplStr string) (string, error) {
+func (uc *ExportUseCase) ExportWithTemplate(commits map[string][]entity.Commit, selected map[string]bool, dateStr string, stats *entity.Statistics, tmplStr string, opts entity.ExportOptions) (string, error) {
 	data := struct {
 		Date    string
 		Commits map[string][]entity.Commit
@@ -121,9 +532,15 @@ func (uc *ExportUseCase) ExportWithTemplate(commits map[string][]entity.Commit,
 	}
 
 	for repo, repoCommits := range commits {
-		if selected[repo] {
-			data.Commits[repo] = repoCommits
+		if !selected[repo] {
+			continue
+		}
+		cased := make([]entity.Commit, len(repoCommits))
+		for i, commit := range repoCommits {
+			commit.Message = opts.MessageCase.Apply(commit.Message)
+			cased[i] = commit
 		}
+		data.Commits[repo] = cased
 	}
 
 	tmpl, err := template.New("summary").Parse(tmplStr)
@@ -139,7 +556,55 @@ func (uc *ExportUseCase) ExportWithTemplate(commits map[string][]entity.Commit,
 	return buf.String(), nil
 }
 
-// SaveToFile saves content to a file.
+// ExportToPRBody renders a pull request description, suitable for
+// gh pr create --body-file.
+func (uc *ExportUseCase) ExportToPRBody(commits map[string][]entity.Commit, selected map[string]bool, dateStr string, stats *entity.Statistics, opts entity.ExportOptions) (string, error) {
+	tmpl := uc.prBodyTemplate
+	if tmpl == "" {
+		tmpl = DefaultPRBodyTemplate
+	}
+	return uc.ExportWithTemplate(commits, selected, dateStr, stats, tmpl, opts)
+}
+
+// postProcessTimeout bounds how long the post-process command may run.
+const postProcessTimeout = 10 * time.Second
+
+// PostProcess pipes content through the post-process command and returns its
+// output. Without a command the content is returned unchanged.
+func (uc *ExportUseCase) PostProcess(content string) (string, error) {
+	if uc.postProcessCommand == "" {
+		return content, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), postProcessTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", uc.postProcessCommand)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", uc.postProcessCommand)
+	}
+	cmd.Stdin = strings.NewReader(content)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("timed out after %s", postProcessTimeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// SaveToFile saves content to a file, applying the configured line endings
+// and byte order mark.
 func (uc *ExportUseCase) SaveToFile(content, filename string) error {
 	dir := filepath.Dir(filename)
 	if dir != "." && dir != "" {
@@ -148,19 +613,90 @@ func (uc *ExportUseCase) SaveToFile(content, filename string) error {
 		}
 	}
 
-	return os.WriteFile(filename, []byte(content), 0644)
+	return os.WriteFile(filename, uc.encodeFile(content), 0644)
+}
+
+// utf8BOM is the UTF-8 byte order mark some Windows tools expect.
+const utf8BOM = "\uFEFF"
+
+// encodeFile converts content to the bytes written by SaveToFile.
+func (uc *ExportUseCase) encodeFile(content string) []byte {
+	if uc.crlf {
+		// Normalize first so existing CRLF isn't doubled.
+		content = strings.ReplaceAll(content, "\r\n", "\n")
+		content = strings.ReplaceAll(content, "\n", "\r\n")
+	}
+	if uc.bom && !strings.HasPrefix(content, utf8BOM) {
+		content = utf8BOM + content
+	}
+	return []byte(content)
 }
 
 // GenerateFilename generates a filename based on date and format.
 func (uc *ExportUseCase) GenerateFilename(startDate string, format entity.ExportFormat) string {
+	if format == entity.FormatPRBody {
+		return uc.prBodyFilename()
+	}
+
 	ext := ".txt"
 	switch format {
 	case entity.FormatMarkdown:
 		ext = ".md"
-	case entity.FormatJSON:
+	case entity.FormatJSON, entity.FormatLegacyJSON:
 		ext = ".json"
+	case entity.FormatHeatmap:
+		ext = ".csv"
+	case entity.FormatGitHubTasks, entity.FormatDaily:
+		ext = ".md"
+	}
+	return fmt.Sprintf("commits-%s%s", entity.FormatDate(startDate, uc.filenameDateFormat), ext)
+}
+
+// prBodyFilename returns the configured PR body file, falling back to the
+// repository's .git directory when run from a checkout.
+func (uc *ExportUseCase) prBodyFilename() string {
+	if uc.prBodyFile != "" {
+		return uc.prBodyFile
+	}
+	if info, err := os.Stat(".git"); err == nil && info.IsDir() {
+		return filepath.Join(".git", "PULLREQUEST_MSG")
+	}
+	return "PULLREQUEST_MSG.md"
+}
+
+// orderedRepos returns the selected repositories in the configured section
+// order.
+func (uc *ExportUseCase) orderedRepos(commits map[string][]entity.Commit, selected map[string]bool) []string {
+	repos := getSelectedReposSorted(commits, selected)
+	if uc.repoOrder == "" || uc.repoOrder == entity.RepoSortName {
+		return repos
 	}
-	return fmt.Sprintf("commits-%s%s", startDate, ext)
+	return SortRepos(repos, commits, uc.repoOrder)
+}
+
+// sortedCommits returns a copy of commits with each repository's commits
+// ordered by date (undated last), then message, then SHA, independent of the
+// order the API returned them in.
+func sortedCommits(commits map[string][]entity.Commit) map[string][]entity.Commit {
+	sorted := make(map[string][]entity.Commit, len(commits))
+	for repo, repoCommits := range commits {
+		ordered := slices.Clone(repoCommits)
+		sort.SliceStable(ordered, func(i, j int) bool {
+			a, b := ordered[i], ordered[j]
+			if a.Date.IsZero() != b.Date.IsZero() {
+				return !a.Date.IsZero()
+			}
+			if !a.Date.Equal(b.Date) {
+				return a.Date.Before(b.Date)
+			}
+			if a.Message != b.Message {
+				return a.Message < b.Message
+			}
+			return a.SHA < b.SHA
+		})
+		sorted[repo] = ordered
+	}
+	return sorted
 }
 
 // getSelectedReposSorted returns a sorted slice of selected repository names.
@@ -200,3 +736,17 @@ Total: {{.Stats.TotalCommits}} commits in {{.Stats.TotalRepositories}} repos
 {{range $commits}}* {{.Message}}
 {{end}}{{end}}`,
 }
+
+// DefaultPRBodyTemplate is the built-in layout for PR body exports.
+const DefaultPRBodyTemplate = `Rollup for {{.Date}}
+
+## Summary
+
+{{if .Stats}}{{.Stats.TotalCommits}} commits across {{.Stats.TotalRepositories}} repositories.
+{{end}}
+## Changes
+{{range $repo, $commits := .Commits}}
+### {{$repo}}
+
+{{range $commits}}- {{.Message}}
+{{end}}{{end}}`
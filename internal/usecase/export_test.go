@@ -0,0 +1,395 @@
+package usecase
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DementevVV/commitsum/internal/domain/entity"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// exportFixture is a small fixture across two repositories.
+func exportFixture() map[string][]entity.Commit {
+	return map[string][]entity.Commit{
+		"acme/api": {
+			{Repository: "acme/api", Message: "Add login", SHA: "a1"},
+			{Repository: "acme/api", Message: "Fix logout", SHA: "a2"},
+		},
+		"acme/web": {
+			{Repository: "acme/web", Message: "Style header", SHA: "w1"},
+		},
+	}
+}
+
+// selectAll selects every repository in commits.
+func selectAll(commits map[string][]entity.Commit) map[string]bool {
+	selected := make(map[string]bool, len(commits))
+	for repo := range commits {
+		selected[repo] = true
+	}
+	return selected
+}
+
+// newTestExportUseCase returns an export use case with a fixed version and clock.
+func newTestExportUseCase() *ExportUseCase {
+	uc := NewExportUseCase()
+	uc.SetVersion("1.2.0")
+	uc.now = func() time.Time { return time.Date(2026, time.February, 2, 18, 4, 5, 0, time.UTC) }
+	return uc
+}
+
+// checkGolden compares got with testdata/name, rewriting it with -update.
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s:\n--- got ---\n%s\n--- want ---\n%s", path, got, want)
+	}
+}
+
+func TestExportFooterGolden(t *testing.T) {
+	commits := exportFixture()
+	stats := NewCommitUseCase(nil, nil).CalculateStatistics(commits, selectAll(commits))
+
+	tests := []struct {
+		name     string
+		markdown bool
+		footer   bool
+		stats    *entity.Statistics
+	}{
+		{name: "text_footer.golden", footer: true, stats: stats},
+		{name: "text_footer_no_stats.golden", footer: true},
+		{name: "text_no_footer.golden", footer: false, stats: stats},
+		{name: "markdown_footer.golden", markdown: true, footer: true, stats: stats},
+		{name: "markdown_no_footer.golden", markdown: true, footer: false, stats: stats},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := newTestExportUseCase()
+			opts := entity.DefaultExportOptions()
+			opts.Footer = tt.footer
+
+			got := uc.ExportToText(commits, selectAll(commits), "Mar 1, 2024", tt.stats, opts)
+			if tt.markdown {
+				got = uc.ExportToMarkdown(commits, selectAll(commits), "Mar 1, 2024", tt.stats, opts)
+			}
+			checkGolden(t, tt.name, got)
+		})
+	}
+}
+
+func TestFooterLineVersion(t *testing.T) {
+	tests := []struct {
+		version       string
+		deterministic bool
+		want          string
+	}{
+		{version: "", want: "Generated by commitsum at 2026-02-02 18:04:05"},
+		{version: "1.2.0", want: "Generated by commitsum v1.2.0 at 2026-02-02 18:04:05"},
+		{version: "dev", want: "Generated by commitsum dev at 2026-02-02 18:04:05"},
+		{version: "1.2.0", deterministic: true, want: "Generated by commitsum v1.2.0"},
+	}
+
+	for _, tt := range tests {
+		uc := newTestExportUseCase()
+		uc.SetVersion(tt.version)
+		if got := uc.footerLine(tt.deterministic); got != tt.want {
+			t.Errorf("footerLine(%v) with version %q = %q, want %q", tt.deterministic, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestExportToGitHubTasksGolden(t *testing.T) {
+	commits := exportFixture()
+	commits["octocat/dotfiles"] = []entity.Commit{{Repository: "octocat/dotfiles", Message: "Tweak vimrc", SHA: "d1"}}
+	selected := map[string]bool{"acme/api": true, "acme/web": true}
+
+	got := NewExportUseCase().ExportToGitHubTasks(commits, selected, "2026-02-02", entity.ExportOptions{})
+	checkGolden(t, "github_tasks.golden", got)
+}
+
+func TestCommitLineSHA(t *testing.T) {
+	const sha = "abc1234def5678"
+
+	tests := []struct {
+		name    string
+		sha     string
+		showSHA bool
+		code    bool
+		want    string
+	}{
+		{name: "off", sha: sha, want: "Add login"},
+		{name: "short SHA", sha: sha, showSHA: true, want: "abc1234 Add login"},
+		{name: "markdown", sha: sha, showSHA: true, code: true, want: "`abc1234` Add login"},
+		{name: "SHA already short", sha: "abc12", showSHA: true, want: "abc12 Add login"},
+		{name: "no SHA", showSHA: true, want: "Add login"},
+		{name: "no SHA markdown", showSHA: true, code: true, want: "Add login"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commit := entity.Commit{Repository: "acme/api", Message: "Add login", SHA: tt.sha}
+			got := commitLine(commit, entity.ExportOptions{ShowSHA: tt.showSHA}, tt.code)
+			if got != tt.want {
+				t.Errorf("commitLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportOptionsPerExporter(t *testing.T) {
+	commits := map[string][]entity.Commit{
+		"acme/api": {
+			{Repository: "acme/api", Message: "add login", SHA: "a1b2c3d4e5", PR: &entity.PullRequest{Number: 42, Title: "Login"}},
+			{Repository: "acme/api", Message: "fix logout", SHA: "f00dfeed99"},
+		},
+		"acme/web": {
+			{Repository: "acme/web", Message: "style header", SHA: "0badcafe11"},
+		},
+	}
+	selected := selectAll(commits)
+	stats := NewCommitUseCase(nil, nil).CalculateStatistics(commits, selected)
+
+	tests := []struct {
+		name   string
+		format entity.ExportFormat
+		set    func(*entity.ExportOptions)
+		want   string
+	}{
+		{name: "stats", format: entity.FormatText, set: func(o *entity.ExportOptions) { o.IncludeStats = true }, want: "Statistics: 3 commits across 2 repositories"},
+		{name: "stats", format: entity.FormatMarkdown, set: func(o *entity.ExportOptions) { o.IncludeStats = true }, want: "## Statistics"},
+		{name: "stats", format: entity.FormatJSON, set: func(o *entity.ExportOptions) { o.IncludeStats = true }, want: `"stats": {`},
+
+		{name: "footer", format: entity.FormatText, set: func(o *entity.ExportOptions) { o.Footer = true }, want: "---\nGenerated by commitsum v1.2.0"},
+		{name: "footer", format: entity.FormatMarkdown, set: func(o *entity.ExportOptions) { o.Footer = true }, want: "*Generated by commitsum v1.2.0"},
+		{name: "footer", format: entity.FormatDaily, set: func(o *entity.ExportOptions) { o.Footer = true }, want: "*Generated by commitsum v1.2.0"},
+
+		{name: "collapsible", format: entity.FormatMarkdown, set: func(o *entity.ExportOptions) { o.CollapsibleMarkdown = true }, want: "<details>\n<summary>acme/api (2)</summary>"},
+
+		{name: "message case", format: entity.FormatText, set: func(o *entity.ExportOptions) { o.MessageCase = entity.MessageCaseSentence }, want: "- Add login"},
+		{name: "message case", format: entity.FormatMarkdown, set: func(o *entity.ExportOptions) { o.MessageCase = entity.MessageCaseSentence }, want: "- Add login"},
+		{name: "message case", format: entity.FormatJSON, set: func(o *entity.ExportOptions) { o.MessageCase = entity.MessageCaseSentence }, want: `"message": "Add login"`},
+		{name: "message case", format: entity.FormatGitHubTasks, set: func(o *entity.ExportOptions) { o.MessageCase = entity.MessageCaseSentence }, want: "- [ ] Add login"},
+		{name: "message case", format: entity.FormatDaily, set: func(o *entity.ExportOptions) { o.MessageCase = entity.MessageCaseSentence }, want: "- Add login"},
+
+		{name: "sha", format: entity.FormatText, set: func(o *entity.ExportOptions) { o.ShowSHA = true }, want: "- a1b2c3d add login"},
+		{name: "sha", format: entity.FormatMarkdown, set: func(o *entity.ExportOptions) { o.ShowSHA = true }, want: "- `a1b2c3d` add login"},
+		{name: "sha", format: entity.FormatGitHubTasks, set: func(o *entity.ExportOptions) { o.ShowSHA = true }, want: "- [ ] `a1b2c3d` add login"},
+		{name: "sha", format: entity.FormatDaily, set: func(o *entity.ExportOptions) { o.ShowSHA = true }, want: "- `a1b2c3d` add login"},
+
+		{name: "aliases", format: entity.FormatText, set: setAlias, want: "[API (acme/api)]"},
+		{name: "aliases", format: entity.FormatMarkdown, set: setAlias, want: "### API (acme/api)"},
+		{name: "aliases", format: entity.FormatJSON, set: setAlias, want: `"alias": "API"`},
+		{name: "aliases", format: entity.FormatCompact, set: setAlias, want: "API: 2"},
+		{name: "aliases", format: entity.FormatGitHubTasks, set: setAlias, want: "### API (acme/api)"},
+		{name: "aliases", format: entity.FormatDaily, set: setAlias, want: "### API (acme/api)"},
+
+		{name: "bullet", format: entity.FormatText, set: func(o *entity.ExportOptions) { o.Bullet = "*" }, want: "  * add login"},
+		{name: "bullet", format: entity.FormatMarkdown, set: func(o *entity.ExportOptions) { o.Bullet = "*" }, want: "\n* add login"},
+		{name: "bullet", format: entity.FormatDaily, set: func(o *entity.ExportOptions) { o.Bullet = "*" }, want: "\n* add login"},
+
+		{name: "watchlist", format: entity.FormatText, set: setWatchlist, want: "No activity: acme/docs"},
+		{name: "watchlist", format: entity.FormatMarkdown, set: setWatchlist, want: "## No Activity\n\n- acme/docs"},
+		{name: "watchlist", format: entity.FormatJSON, set: setWatchlist, want: `"no_activity": [`},
+
+		{name: "group by PR", format: entity.FormatText, set: func(o *entity.ExportOptions) { o.GroupByPR = true }, want: "  #42 Login\n    - add login"},
+		{name: "group by PR", format: entity.FormatMarkdown, set: func(o *entity.ExportOptions) { o.GroupByPR = true }, want: "- **#42 Login**\n  - add login"},
+	}
+
+	uc := newTestExportUseCase()
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+string(tt.format), func(t *testing.T) {
+			var opts entity.ExportOptions
+			without, err := uc.Render(tt.format, commits, selected, "2026-02-02", stats, nil, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(without, tt.want) {
+				t.Fatalf("output without the option already contains %q:\n%s", tt.want, without)
+			}
+
+			tt.set(&opts)
+			with, err := uc.Render(tt.format, commits, selected, "2026-02-02", stats, nil, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(with, tt.want) {
+				t.Errorf("output lacks %q:\n%s", tt.want, with)
+			}
+		})
+	}
+}
+
+// setAlias names acme/api "API".
+func setAlias(o *entity.ExportOptions) {
+	o.RepoAliases = map[string]string{"acme/api": "API"}
+}
+
+// setWatchlist watches acme/docs, which has no commits.
+func setWatchlist(o *entity.ExportOptions) {
+	o.Watchlist = []string{"acme/api", "acme/docs"}
+}
+
+func TestSaveToFileEncoding(t *testing.T) {
+	const content = "Summary\nacme/api\r\n  - add login\n"
+
+	tests := []struct {
+		lineEnding string
+		bom        bool
+		want       string
+	}{
+		{"lf", false, content},
+		{"lf", true, "\xef\xbb\xbf" + content},
+		{"crlf", false, "Summary\r\nacme/api\r\n  - add login\r\n"},
+		{"crlf", true, "\xef\xbb\xbfSummary\r\nacme/api\r\n  - add login\r\n"},
+		{"", false, content},
+	}
+
+	for _, tt := range tests {
+		uc := newTestExportUseCase()
+		uc.SetFileEncoding(tt.lineEnding, tt.bom)
+		path := filepath.Join(t.TempDir(), "summary.txt")
+		if err := uc.SaveToFile(content, path); err != nil {
+			t.Fatalf("SaveToFile: %v", err)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("%q bom=%v: wrote %q, want %q", tt.lineEnding, tt.bom, got, tt.want)
+		}
+	}
+}
+
+func TestFileEncodingLeavesRenderedContent(t *testing.T) {
+	commits := exportFixture()
+	selected := selectAll(commits)
+	stats := NewCommitUseCase(nil, nil).CalculateStatistics(commits, selected)
+
+	// Rendered content is what the clipboard receives, so it stays LF.
+	uc := newTestExportUseCase()
+	uc.SetFileEncoding("crlf", true)
+	got, err := uc.Render(entity.FormatText, commits, selected, "2026-02-02", stats, nil, entity.ExportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "\r") || strings.HasPrefix(got, utf8BOM) {
+		t.Errorf("Render applied the file encoding: %q", got)
+	}
+}
+
+func TestExportRepoOrder(t *testing.T) {
+	commits := map[string][]entity.Commit{
+		"acme/api": {
+			{Repository: "acme/api", Message: "api change", SHA: "a1"},
+		},
+		"acme/web": {
+			{Repository: "acme/web", Message: "web change 1", SHA: "w1"},
+			{Repository: "acme/web", Message: "web change 2", SHA: "w2"},
+			{Repository: "acme/web", Message: "web change 3", SHA: "w3"},
+		},
+		"zeta/cli": {
+			{Repository: "zeta/cli", Message: "cli change 1", SHA: "z1"},
+			{Repository: "zeta/cli", Message: "cli change 2", SHA: "z2"},
+		},
+	}
+	selected := selectAll(commits)
+	stats := NewCommitUseCase(nil, nil).CalculateStatistics(commits, selected)
+
+	tests := []struct {
+		order entity.RepoSortOrder
+		want  []string
+	}{
+		{"", []string{"api change", "web change 1", "cli change 1"}},
+		{entity.RepoSortName, []string{"api change", "web change 1", "cli change 1"}},
+		{entity.RepoSortCount, []string{"web change 1", "cli change 1", "api change"}},
+	}
+
+	for _, format := range []entity.ExportFormat{entity.FormatText, entity.FormatMarkdown, entity.FormatGitHubTasks} {
+		for _, tt := range tests {
+			uc := newTestExportUseCase()
+			uc.SetRepoOrder(tt.order)
+			got, err := uc.Render(format, commits, selected, "2026-02-02", stats, nil, entity.ExportOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Each repo's first commit marks where its section starts.
+			prev := -1
+			for _, message := range tt.want {
+				i := strings.Index(got, message)
+				if i <= prev {
+					t.Errorf("%s with order %q: sections not in order %v:\n%s", format, tt.order, tt.want, got)
+					break
+				}
+				prev = i
+			}
+		}
+	}
+}
+
+func TestDeterministicExport(t *testing.T) {
+	at := time.Date(2026, time.February, 2, 9, 0, 0, 0, time.UTC)
+	commits := func(reverse bool) map[string][]entity.Commit {
+		api := []entity.Commit{
+			{Repository: "acme/api", Message: "add login", SHA: "a1", Date: at},
+			{Repository: "acme/api", Message: "fix logout", SHA: "a2", Date: at.Add(time.Hour)},
+			{Repository: "acme/api", Message: "bump deps", SHA: "a3", Date: at},
+		}
+		if reverse {
+			slices.Reverse(api)
+		}
+		return map[string][]entity.Commit{
+			"acme/api": api,
+			"acme/web": {{Repository: "acme/web", Message: "style header", SHA: "w1", Date: at}},
+		}
+	}
+
+	// Render the same commits in a different order a second apart.
+	export := func(format entity.ExportFormat, reverse bool, now time.Time) string {
+		t.Helper()
+		uc := newTestExportUseCase()
+		uc.now = func() time.Time { return now }
+		c := commits(reverse)
+		selected := selectAll(c)
+		stats := NewCommitUseCase(nil, nil).CalculateStatistics(c, selected)
+		opts := entity.DefaultExportOptions()
+		opts.Footer = true
+		opts.Deterministic = true
+		got, err := uc.Render(format, c, selected, "2026-02-02", stats, nil, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	formats := []entity.ExportFormat{entity.FormatText, entity.FormatMarkdown, entity.FormatDaily, entity.FormatJSON, entity.FormatGitHubTasks}
+	for _, format := range formats {
+		first := export(format, false, at)
+		second := export(format, true, at.Add(time.Second))
+		if first != second {
+			t.Errorf("%s exports differ:\n--- first ---\n%s\n--- second ---\n%s", format, first, second)
+		}
+	}
+	if got := export(entity.FormatJSON, false, at); strings.Contains(got, "generated_at") {
+		t.Errorf("deterministic JSON has a generated_at field:\n%s", got)
+	}
+}